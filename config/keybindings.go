@@ -4,15 +4,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // AppConfig is the root configuration structure loaded from config.yaml.
 type AppConfig struct {
-	KeyBindings KeyBindings  `yaml:"keybindings"`
-	UI          UIConfig     `yaml:"ui"`
-	Docker      DockerConfig `yaml:"docker"`
+	KeyBindings  KeyBindings        `yaml:"keybindings"`
+	UI           UIConfig           `yaml:"ui"`
+	Docker       DockerConfig       `yaml:"docker"`
+	Logs         LogsConfig         `yaml:"logs"`
+	Top          TopConfig          `yaml:"top"`
+	AlertMonitor AlertMonitorConfig `yaml:"alert_monitor"`
+	Alerts       []AlertRule        `yaml:"alerts"`
+	Safety       SafetyConfig       `yaml:"safety"`
+	Hosts        []HostProfile      `yaml:"hosts"`
 }
 
 // KeyBindings holds all configurable key bindings
@@ -21,6 +28,10 @@ type KeyBindings struct {
 	Container  ContainerKeys  `yaml:"container"`
 	Views      ViewKeys       `yaml:"views"`
 	Logs       LogKeys        `yaml:"logs"`
+	Top        TopKeys        `yaml:"top"`
+	Alerts     AlertKeys      `yaml:"alerts"`
+	Events     EventKeys      `yaml:"events"`
+	Selection  SelectionKeys  `yaml:"selection"`
 	Commands   CommandKeys    `yaml:"commands"`
 	General    GeneralKeys    `yaml:"general"`
 }
@@ -35,6 +46,9 @@ type NavigationKeys struct {
 	SwitchVolume    []string `yaml:"switch_volume"`
 	SwitchImage     []string `yaml:"switch_image"`
 	SwitchNetwork   []string `yaml:"switch_network"`
+	SwitchTop       []string `yaml:"switch_top"`
+	SwitchAlerts    []string `yaml:"switch_alerts"`
+	SwitchEvents    []string `yaml:"switch_events"`
 }
 
 type ContainerKeys struct {
@@ -50,15 +64,50 @@ type ContainerKeys struct {
 	RefreshStats []string `yaml:"refresh_stats"`
 }
 
+// TopKeys are the bindings of the resource usage overview.
+type TopKeys struct {
+	Jump        []string `yaml:"jump"`
+	SortNext    []string `yaml:"sort_next"`
+	SortPrev    []string `yaml:"sort_prev"`
+	SortReverse []string `yaml:"sort_reverse"`
+}
+
+// AlertKeys are the bindings of the alerts view.
+type AlertKeys struct {
+	Jump  []string `yaml:"jump"`
+	Clear []string `yaml:"clear"`
+}
+
+// EventKeys are the bindings of the events timeline.
+type EventKeys struct {
+	Jump      []string `yaml:"jump"`
+	CycleType []string `yaml:"cycle_type"`
+}
+
+// SelectionKeys mark rows of the resource lists for bulk actions.
+type SelectionKeys struct {
+	Toggle  []string `yaml:"toggle"`
+	MarkAll []string `yaml:"mark_all"`
+	Invert  []string `yaml:"invert"`
+}
+
 type ViewKeys struct {
 	Back []string `yaml:"back"`
 }
 
 type LogKeys struct {
-	Search     []string `yaml:"search"`
-	NextResult []string `yaml:"next_result"`
-	PrevResult []string `yaml:"prev_result"`
-	Follow     []string `yaml:"follow"`
+	Search       []string `yaml:"search"`
+	NextResult   []string `yaml:"next_result"`
+	PrevResult   []string `yaml:"prev_result"`
+	Follow       []string `yaml:"follow"`
+	StreamFilter []string `yaml:"stream_filter"`
+	CycleSource  []string `yaml:"cycle_source"`
+	LoadOlder    []string `yaml:"load_older"`
+	JSONView     []string `yaml:"json_view"`
+	Expand       []string `yaml:"expand"`
+	LevelFilter  []string `yaml:"level_filter"`
+	SmartCase    []string `yaml:"smart_case"`
+	FilterMode   []string `yaml:"filter_mode"`
 }
 
 type CommandKeys struct {
@@ -85,9 +134,119 @@ type DockerConfig struct {
 	// unix:///var/run/docker.sock, tcp://host:2376, or ssh://user@host
 	// Empty value keeps Docker SDK environment/default behavior.
 	Host string `yaml:"host"`
-	// AutoRefreshSeconds controls container list auto-refresh interval.
+	// AutoRefreshSeconds is the container list polling interval, used while
+	// the daemon event stream is disconnected.
 	// Must be >= 1. Default is 10 seconds.
 	AutoRefreshSeconds int `yaml:"auto_refresh_seconds"`
+	// Timeouts bound each kind of request made to the daemon.
+	Timeouts TimeoutConfig `yaml:"timeouts"`
+	// Context is the Docker CLI context or hosts profile to connect to on
+	// startup. Empty connects to Host, or DOCKER_HOST and the default socket.
+	Context string `yaml:"context"`
+	// ReadOnly disables every operation that changes the host, such as
+	// start, stop, restart, delete and exec. The --read-only flag sets it too.
+	ReadOnly bool `yaml:"read_only"`
+}
+
+// TimeoutConfig holds how long each kind of Docker request may take before
+// it is abandoned. Zero waits without a deadline; a running request can
+// still be cancelled with esc or :cancel. Streams that stay open, like
+// followed logs, stats and events, are not bounded.
+type TimeoutConfig struct {
+	// List covers loading the container, volume, image and network lists
+	// and the event history.
+	List time.Duration `yaml:"list"`
+	// Inspect covers inspecting a single container.
+	Inspect time.Duration `yaml:"inspect"`
+	// Action covers start, stop, restart and delete.
+	Action time.Duration `yaml:"action"`
+	// Logs covers loading a page of log history.
+	Logs time.Duration `yaml:"logs"`
+	// Stats covers one stats sample of a container.
+	Stats time.Duration `yaml:"stats"`
+}
+
+// LogsConfig holds log viewer preferences.
+type LogsConfig struct {
+	// TailLines is how many lines are loaded when the log view opens.
+	TailLines int `yaml:"tail_lines"`
+	// PageSize is how many older lines each "load older" request fetches.
+	// Defaults to TailLines.
+	PageSize int `yaml:"page_size"`
+	// MaxLines caps the in-memory log buffer. The oldest lines are dropped
+	// once a followed stream grows past it.
+	MaxLines int `yaml:"max_lines"`
+	// JSONFields are the keys shown as columns when JSON view is on.
+	// Dotted keys such as "http.status" reach into nested objects.
+	JSONFields []string `yaml:"json_fields"`
+	// SmartCase makes searches containing upper-case letters case-sensitive.
+	SmartCase bool `yaml:"smart_case"`
+	// SearchContext is how many lines around each match filter mode shows.
+	SearchContext int `yaml:"search_context"`
+	// LevelPatterns adds regular expressions per level ("error", "warn",
+	// "info", "debug") that are tried before the built-in level detection.
+	LevelPatterns map[string][]string `yaml:"level_patterns"`
+}
+
+// TopConfig holds settings of the resource usage overview.
+type TopConfig struct {
+	// RefreshSeconds is the pause between two rounds of stats requests.
+	RefreshSeconds int `yaml:"refresh_seconds"`
+	// Parallelism caps how many containers are queried at the same time.
+	Parallelism int `yaml:"parallelism"`
+}
+
+// AlertMonitorConfig holds settings of the background alert monitor. They
+// are separate from TopConfig so the overview can refresh at its own pace.
+type AlertMonitorConfig struct {
+	// IntervalSeconds is the pause between two rounds of samples.
+	IntervalSeconds int `yaml:"interval_seconds"`
+	// Parallelism caps how many containers are sampled at the same time.
+	Parallelism int `yaml:"parallelism"`
+}
+
+// AlertRule raises an alert when a matching container stays above a
+// threshold. A zero threshold disables that metric.
+type AlertRule struct {
+	// Container is a glob matched against container names, e.g. "api*".
+	Container string `yaml:"container"`
+	// CPUPercent is the CPU threshold, where 100 is one full core.
+	CPUPercent float64 `yaml:"cpu_percent"`
+	// MemoryPercent is the threshold relative to the memory limit.
+	MemoryPercent float64 `yaml:"memory_percent"`
+	// For is how long the threshold must be exceeded before the alert fires.
+	For time.Duration `yaml:"for"`
+	// Command is run through "sh -c" when the alert fires, with the alert
+	// described in GDOCKER_ALERT_* environment variables.
+	Command string `yaml:"command"`
+	// Webhook receives a JSON POST when the alert fires.
+	Webhook string `yaml:"webhook"`
+}
+
+// HostProfile is a named Docker endpoint that :context can switch to, next
+// to the contexts of the Docker CLI.
+type HostProfile struct {
+	Name        string `yaml:"name"`
+	Host        string `yaml:"host"` // e.g. ssh://deploy@prod-1 or tcp://10.0.0.5:2375
+	Description string `yaml:"description"`
+}
+
+// When a delete asks for confirmation, see SafetyConfig.ConfirmDelete.
+const (
+	ConfirmAlways         = "always"
+	ConfirmOnlyForVolumes = "only-for-volumes"
+	ConfirmNever          = "never"
+)
+
+// SafetyConfig guards destructive operations.
+type SafetyConfig struct {
+	// ConfirmDelete is when a delete shows a confirmation dialog first:
+	// "always", "only-for-volumes" or "never".
+	ConfirmDelete string `yaml:"confirm_delete"`
+	// Protected are globs matched against container, volume, image and
+	// network names, e.g. "*-db-*". Matching items cannot be deleted from
+	// the UI, whatever ConfirmDelete says.
+	Protected []string `yaml:"protected"`
 }
 
 // Default returns the default key bindings
@@ -103,6 +262,9 @@ func Default() *KeyBindings {
 			SwitchVolume:    []string{"2"},
 			SwitchImage:     []string{"3"},
 			SwitchNetwork:   []string{"4"},
+			SwitchTop:       []string{"5"},
+			SwitchAlerts:    []string{"6"},
+			SwitchEvents:    []string{"7"},
 		},
 		Container: ContainerKeys{
 			Restart:      []string{"r"},
@@ -120,10 +282,37 @@ func Default() *KeyBindings {
 			Back: []string{"esc"},
 		},
 		Logs: LogKeys{
-			Search:     []string{"?"},
-			NextResult: []string{"n"},
-			PrevResult: []string{"N"},
-			Follow:     []string{"f"},
+			Search:       []string{"?"},
+			NextResult:   []string{"n"},
+			PrevResult:   []string{"N"},
+			Follow:       []string{"f"},
+			StreamFilter: []string{"s"},
+			CycleSource:  []string{"c"},
+			LoadOlder:    []string{"K"},
+			JSONView:     []string{"J"},
+			Expand:       []string{"x"},
+			LevelFilter:  []string{"L"},
+			SmartCase:    []string{"C"},
+			FilterMode:   []string{"F"},
+		},
+		Top: TopKeys{
+			Jump:        []string{"enter"},
+			SortNext:    []string{">"},
+			SortPrev:    []string{"<"},
+			SortReverse: []string{"I"},
+		},
+		Alerts: AlertKeys{
+			Jump:  []string{"enter"},
+			Clear: []string{"x"},
+		},
+		Events: EventKeys{
+			Jump:      []string{"enter"},
+			CycleType: []string{"T"},
+		},
+		Selection: SelectionKeys{
+			Toggle:  []string{"m"},
+			MarkAll: []string{"M"},
+			Invert:  []string{"*"},
 		},
 		Commands: CommandKeys{
 			Enter: []string{":"},
@@ -149,9 +338,13 @@ func DefaultUI() *UIConfig {
 // DefaultAppConfig returns a full default config.
 func DefaultAppConfig() *AppConfig {
 	return &AppConfig{
-		KeyBindings: *Default(),
-		UI:          *DefaultUI(),
-		Docker:      *DefaultDocker(),
+		KeyBindings:  *Default(),
+		UI:           *DefaultUI(),
+		Docker:       *DefaultDocker(),
+		Logs:         *DefaultLogs(),
+		Top:          *DefaultTop(),
+		AlertMonitor: *DefaultAlertMonitor(),
+		Safety:       *DefaultSafety(),
 	}
 }
 
@@ -160,9 +353,49 @@ func DefaultDocker() *DockerConfig {
 	return &DockerConfig{
 		Host:               "",
 		AutoRefreshSeconds: 10,
+		Timeouts: TimeoutConfig{
+			List:    30 * time.Second,
+			Inspect: 15 * time.Second,
+			Action:  60 * time.Second,
+			Logs:    60 * time.Second,
+			Stats:   10 * time.Second,
+		},
+	}
+}
+
+// DefaultLogs returns default log viewer settings.
+func DefaultLogs() *LogsConfig {
+	return &LogsConfig{
+		TailLines:     100,
+		PageSize:      100,
+		MaxLines:      10000,
+		JSONFields:    []string{"level", "msg"},
+		SmartCase:     true,
+		SearchContext: 2,
+	}
+}
+
+// DefaultTop returns default resource overview settings.
+func DefaultTop() *TopConfig {
+	return &TopConfig{
+		RefreshSeconds: 2,
+		Parallelism:    8,
 	}
 }
 
+// DefaultAlertMonitor returns default alert monitor settings.
+func DefaultAlertMonitor() *AlertMonitorConfig {
+	return &AlertMonitorConfig{
+		IntervalSeconds: 5,
+		Parallelism:     4,
+	}
+}
+
+// DefaultSafety returns the default safety policy: every delete asks first.
+func DefaultSafety() *SafetyConfig {
+	return &SafetyConfig{ConfirmDelete: ConfirmAlways}
+}
+
 // sanitize applies value bounds for numeric UI options.
 func (c *AppConfig) sanitize() {
 	if c.UI.MaxProjectPreviewItems < 1 {
@@ -175,9 +408,62 @@ func (c *AppConfig) sanitize() {
 		c.UI.MaxImageTagPreview = 1
 	}
 	c.Docker.Host = strings.TrimSpace(c.Docker.Host)
+	c.Docker.Context = strings.TrimSpace(c.Docker.Context)
+	for i := range c.Hosts {
+		c.Hosts[i].Name = strings.TrimSpace(c.Hosts[i].Name)
+		c.Hosts[i].Host = strings.TrimSpace(c.Hosts[i].Host)
+	}
 	if c.Docker.AutoRefreshSeconds < 1 {
 		c.Docker.AutoRefreshSeconds = 10
 	}
+	for _, d := range []*time.Duration{&c.Docker.Timeouts.List, &c.Docker.Timeouts.Inspect, &c.Docker.Timeouts.Action, &c.Docker.Timeouts.Logs, &c.Docker.Timeouts.Stats} {
+		if *d < 0 {
+			*d = 0
+		}
+	}
+	if c.Logs.TailLines < 1 {
+		c.Logs.TailLines = 100
+	}
+	if c.Logs.PageSize < 1 {
+		c.Logs.PageSize = c.Logs.TailLines
+	}
+	if c.Logs.MaxLines < c.Logs.TailLines {
+		c.Logs.MaxLines = c.Logs.TailLines
+	}
+	if c.Logs.SearchContext < 0 {
+		c.Logs.SearchContext = 0
+	}
+	if len(c.Logs.JSONFields) == 0 {
+		c.Logs.JSONFields = DefaultLogs().JSONFields
+	}
+	if c.Top.RefreshSeconds < 1 {
+		c.Top.RefreshSeconds = 1
+	}
+	if c.Top.Parallelism < 1 {
+		c.Top.Parallelism = 1
+	}
+	if c.AlertMonitor.IntervalSeconds < 1 {
+		c.AlertMonitor.IntervalSeconds = 1
+	}
+	if c.AlertMonitor.Parallelism < 1 {
+		c.AlertMonitor.Parallelism = 1
+	}
+	// An unknown policy falls back to the safest one
+	switch c.Safety.ConfirmDelete {
+	case ConfirmAlways, ConfirmOnlyForVolumes, ConfirmNever:
+	default:
+		c.Safety.ConfirmDelete = ConfirmAlways
+	}
+	for i := range c.Alerts {
+		rule := &c.Alerts[i]
+		rule.Container = strings.TrimSpace(rule.Container)
+		if rule.Container == "" {
+			rule.Container = "*"
+		}
+		if rule.For < 0 {
+			rule.For = 0
+		}
+	}
 }
 
 // Load loads app config from a config file, falling back to defaults.
@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gdocker/config"
+	"gdocker/models"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// alertHookTimeout bounds how long a command or webhook may take.
+const alertHookTimeout = 10 * time.Second
+
+// LoadAlertStats samples every running container matched by an alert rule.
+// It lists containers itself so the monitor keeps working whichever view
+// is open.
+func LoadAlertStats(m *models.Model) tea.Cmd {
+	cli := m.DockerClient
+	prev := maps.Clone(m.AlertStats)
+	rules := slices.Clone(m.AlertRules)
+	parallelism := m.AlertMonitorSettings().Parallelism
+	t := m.TimeoutSettings()
+	matches := func(name string) bool {
+		for _, rule := range rules {
+			if models.AlertRuleMatches(rule, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := withTimeout(context.Background(), t.List)
+		defer cancel()
+		list, err := cli.ContainerList(ctx, client.ContainerListOptions{})
+		if err != nil {
+			return models.AlertStatsLoadedMsg{Time: time.Now(), Err: ctxError(ctx, err, t.List)}
+		}
+
+		names := make(map[string]string)
+		var ids []string
+		for _, c := range list.Items {
+			name := strings.TrimPrefix(c.Names[0], "/")
+			if matches(name) {
+				id := c.ID[:12]
+				names[id] = name
+				ids = append(ids, id)
+			}
+		}
+
+		stats, _ := sampleContainerStats(cli, ids, prev, parallelism, t.Stats)
+		return models.AlertStatsLoadedMsg{Time: time.Now(), Names: names, Stats: stats}
+	}
+}
+
+// alertPayload is the JSON body posted to a webhook.
+type alertPayload struct {
+	Container   string  `json:"container"`
+	ContainerID string  `json:"container_id"`
+	Metric      string  `json:"metric"`
+	Value       float64 `json:"value"`
+	Threshold   float64 `json:"threshold"`
+	Since       string  `json:"since"`
+	Message     string  `json:"message"`
+}
+
+// NotifyAlert runs the command and webhook hooks of the rule that fired.
+func NotifyAlert(m *models.Model, alert models.Alert) tea.Cmd {
+	rule := m.AlertRules[alert.Rule]
+	if rule.Command == "" && rule.Webhook == "" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), alertHookTimeout)
+		defer cancel()
+
+		var errs []string
+		if rule.Command != "" {
+			if err := runAlertCommand(ctx, rule, alert); err != nil {
+				errs = append(errs, "command: "+err.Error())
+			}
+		}
+		if rule.Webhook != "" {
+			if err := postAlertWebhook(ctx, rule, alert); err != nil {
+				errs = append(errs, "webhook: "+err.Error())
+			}
+		}
+
+		var err error
+		if len(errs) > 0 {
+			err = fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+		return models.AlertNotifiedMsg{Alert: alert, Err: err}
+	}
+}
+
+func runAlertCommand(ctx context.Context, rule config.AlertRule, alert models.Alert) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", rule.Command)
+	cmd.Env = append(os.Environ(),
+		"GDOCKER_ALERT_CONTAINER="+alert.ContainerName,
+		"GDOCKER_ALERT_CONTAINER_ID="+alert.ContainerID,
+		"GDOCKER_ALERT_METRIC="+string(alert.Metric),
+		fmt.Sprintf("GDOCKER_ALERT_VALUE=%.1f", alert.Value),
+		fmt.Sprintf("GDOCKER_ALERT_THRESHOLD=%.1f", alert.Threshold),
+		"GDOCKER_ALERT_MESSAGE="+alert.Message(),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func postAlertWebhook(ctx context.Context, rule config.AlertRule, alert models.Alert) error {
+	body, err := json.Marshal(alertPayload{
+		Container:   alert.ContainerName,
+		ContainerID: alert.ContainerID,
+		Metric:      string(alert.Metric),
+		Value:       alert.Value,
+		Threshold:   alert.Threshold,
+		Since:       alert.Since.Format(time.RFC3339),
+		Message:     alert.Message(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// RebuildAlertItems lists active alerts first, then resolved ones, each
+// newest first.
+func RebuildAlertItems(m *models.Model) {
+	var selected *models.Alert
+	if m.Cursor >= 0 && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsAlert {
+		a := *m.Items[m.Cursor].Alert
+		selected = &a
+	}
+
+	m.Items = []models.ListItem{}
+	for i := range m.Alerts {
+		m.Items = append(m.Items, models.ListItem{
+			IsAlert: true,
+			Alert:   &m.Alerts[i],
+			Index:   i,
+		})
+	}
+
+	sort.SliceStable(m.Items, func(i, j int) bool {
+		a, b := m.Items[i].Alert, m.Items[j].Alert
+		if a.Active() != b.Active() {
+			return a.Active()
+		}
+		return a.FiredAt.After(b.FiredAt)
+	})
+
+	m.Cursor = min(max(m.Cursor, 0), max(len(m.Items)-1, 0))
+	if selected == nil {
+		return
+	}
+	for i, item := range m.Items {
+		a := item.Alert
+		if a.ContainerID == selected.ContainerID && a.Rule == selected.Rule &&
+			a.Metric == selected.Metric && a.FiredAt.Equal(selected.FiredAt) {
+			m.Cursor = i
+			break
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package docker
+
+import (
+	"encoding/json"
+	"gdocker/config"
+	"gdocker/models"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
+
+// contextMeta is the part of a Docker CLI context's meta.json GDocker uses.
+type contextMeta struct {
+	Name     string
+	Metadata struct {
+		Description string
+	}
+	Endpoints map[string]struct {
+		Host string
+	}
+}
+
+// dockerConfigDir is where the Docker CLI keeps its configuration.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// LoadContexts lists the daemons :context can switch to: "default" for the
+// configured host or the environment, then the Docker CLI contexts and the
+// hosts profiles of the config by name. A profile replaces a CLI context of
+// the same name. Contexts that cannot be read are skipped.
+func LoadContexts(appConfig *config.AppConfig) []models.DockerContext {
+	byName := make(map[string]models.DockerContext)
+	for _, c := range readCLIContexts(dockerConfigDir()) {
+		byName[c.Name] = c
+	}
+	for _, p := range appConfig.Hosts {
+		if p.Name == "" || p.Name == models.DefaultContext {
+			continue
+		}
+		byName[p.Name] = models.DockerContext{Name: p.Name, Host: p.Host, Description: p.Description, Source: "config"}
+	}
+
+	contexts := []models.DockerContext{{
+		Name:        models.DefaultContext,
+		Host:        appConfig.Docker.Host,
+		Description: "Configured host, DOCKER_HOST or the default socket",
+	}}
+	var names []string
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		contexts = append(contexts, byName[name])
+	}
+	return contexts
+}
+
+// readCLIContexts reads the contexts the Docker CLI stores under
+// contexts/meta/<digest>/meta.json. TLS material, if any, is in the matching
+// contexts/tls/<digest>/docker directory.
+func readCLIContexts(dir string) []models.DockerContext {
+	if dir == "" {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "contexts", "meta", "*", "meta.json"))
+
+	var contexts []models.DockerContext
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var meta contextMeta
+		if err := json.Unmarshal(data, &meta); err != nil || meta.Name == "" || meta.Name == models.DefaultContext {
+			continue
+		}
+		endpoint, ok := meta.Endpoints["docker"]
+		if !ok {
+			continue
+		}
+
+		c := models.DockerContext{Name: meta.Name, Host: endpoint.Host, Description: meta.Metadata.Description, Source: "docker"}
+		tlsDir := filepath.Join(dir, "contexts", "tls", filepath.Base(filepath.Dir(path)), "docker")
+		if _, err := os.Stat(filepath.Join(tlsDir, "ca.pem")); err == nil {
+			c.TLSDir = tlsDir
+		}
+		contexts = append(contexts, c)
+	}
+	return contexts
+}
+
+// newClient opens a client for c. The default context keeps the Docker
+// SDK's environment handling; the others use only their own endpoint.
+func newClient(c models.DockerContext) (*client.Client, error) {
+	var opts []client.Opt
+	if c.Name == models.DefaultContext {
+		opts = append(opts, client.FromEnv)
+	}
+	opts = append(opts, client.WithAPIVersionNegotiation())
+	if c.Host != "" {
+		opts = append(opts, client.WithHost(c.Host))
+	}
+	if c.TLSDir != "" {
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(c.TLSDir, "ca.pem"),
+			filepath.Join(c.TLSDir, "cert.pem"),
+			filepath.Join(c.TLSDir, "key.pem"),
+		))
+	}
+	return client.NewClientWithOpts(opts...)
+}
+
+// Connect opens a client for another daemon.
+func Connect(m *models.Model, c models.DockerContext) (models.DockerAPI, error) {
+	return newClient(c)
+}
+
+// activeContext returns the context GDocker is connected to.
+func activeContext(m *models.Model) models.DockerContext {
+	for _, c := range m.Contexts {
+		if c.Name == m.Context {
+			return c
+		}
+	}
+	return models.DockerContext{Name: models.DefaultContext}
+}
+
+// dockerCLI builds a docker CLI command that talks to the same daemon as
+// the client. CLI contexts are selected with DOCKER_CONTEXT, which the CLI
+// ignores while DOCKER_HOST is set, so that is dropped. Other endpoints are
+// passed with --host and, when they use TLS, the certificates of TLSDir.
+func dockerCLI(m *models.Model, args ...string) *exec.Cmd {
+	c := activeContext(m)
+	var flags []string
+	var env []string
+	switch {
+	case c.Source == "docker":
+		for _, kv := range os.Environ() {
+			if !strings.HasPrefix(kv, "DOCKER_HOST=") {
+				env = append(env, kv)
+			}
+		}
+		env = append(env, "DOCKER_CONTEXT="+c.Name)
+	case c.Host != "":
+		flags = append(flags, "--host", c.Host)
+		if c.TLSDir != "" {
+			flags = append(flags, "--tlsverify",
+				"--tlscacert", filepath.Join(c.TLSDir, "ca.pem"),
+				"--tlscert", filepath.Join(c.TLSDir, "cert.pem"),
+				"--tlskey", filepath.Join(c.TLSDir, "key.pem"),
+			)
+		}
+	}
+	cmd := exec.Command("docker", append(flags, args...)...)
+	cmd.Env = env // nil keeps the environment of GDocker
+	return cmd
+}
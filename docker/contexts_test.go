@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gdocker/config"
+	"gdocker/models"
+)
+
+// writeContext stores a Docker CLI context the way `docker context create`
+// does, optionally with TLS material.
+func writeContext(t *testing.T, dir, digest, meta string, tls bool) {
+	t.Helper()
+	metaDir := filepath.Join(dir, "contexts", "meta", digest)
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if tls {
+		tlsDir := filepath.Join(dir, "contexts", "tls", digest, "docker")
+		if err := os.MkdirAll(tlsDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tlsDir, "ca.pem"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadContexts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	writeContext(t, dir, "aaaa", `{"Name":"staging","Metadata":{"Description":"Staging cluster"},"Endpoints":{"docker":{"Host":"tcp://staging:2376"}}}`, true)
+	writeContext(t, dir, "bbbb", `{"Name":"prod","Endpoints":{"docker":{"Host":"ssh://ops@prod-1"}}}`, false)
+	writeContext(t, dir, "cccc", `{"Name":"k8s","Endpoints":{"kubernetes":{}}}`, false)
+	writeContext(t, dir, "dddd", `not json`, false)
+
+	cfg := config.DefaultAppConfig()
+	cfg.Docker.Host = "unix:///run/user/1000/docker.sock"
+	cfg.Hosts = []config.HostProfile{
+		{Name: "prod", Host: "ssh://deploy@prod-2"},
+		{Name: "lab", Host: "tcp://10.0.0.5:2375", Description: "Test rack"},
+	}
+
+	got := LoadContexts(cfg)
+	want := []models.DockerContext{
+		{Name: "default", Host: "unix:///run/user/1000/docker.sock", Description: "Configured host, DOCKER_HOST or the default socket"},
+		{Name: "lab", Host: "tcp://10.0.0.5:2375", Description: "Test rack", Source: "config"},
+		{Name: "prod", Host: "ssh://deploy@prod-2", Source: "config"},
+		{Name: "staging", Host: "tcp://staging:2376", Description: "Staging cluster", Source: "docker", TLSDir: filepath.Join(dir, "contexts", "tls", "aaaa", "docker")},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d contexts, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("context %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDockerCLIUsesActiveContext(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+	m := models.NewModel(nil, Services{}, nil)
+	m.Contexts = []models.DockerContext{
+		{Name: "default"},
+		{Name: "staging", Host: "tcp://staging:2376", Source: "docker", TLSDir: "/tls/staging"},
+		{Name: "lab", Host: "tcp://10.0.0.5:2376", Source: "config", TLSDir: "/tls/lab"},
+	}
+
+	tests := []struct {
+		context string
+		args    []string
+		env     []string // Wanted in the environment; nil keeps GDocker's
+		unset   string   // Must not be in the environment
+	}{
+		{context: "default", args: []string{"docker", "exec", "-it", "web", "/bin/sh"}},
+		{
+			context: "staging",
+			args:    []string{"docker", "exec", "-it", "web", "/bin/sh"},
+			env:     []string{"DOCKER_CONTEXT=staging"},
+			unset:   "DOCKER_HOST=",
+		},
+		{
+			context: "lab",
+			args: []string{"docker", "--host", "tcp://10.0.0.5:2376", "--tlsverify",
+				"--tlscacert", "/tls/lab/ca.pem", "--tlscert", "/tls/lab/cert.pem", "--tlskey", "/tls/lab/key.pem",
+				"exec", "-it", "web", "/bin/sh"},
+		},
+	}
+	for _, tt := range tests {
+		m.Context = tt.context
+		cmd := dockerCLI(&m, "exec", "-it", "web", "/bin/sh")
+		if !slices.Equal(cmd.Args, tt.args) {
+			t.Errorf("%s: args = %q, want %q", tt.context, cmd.Args, tt.args)
+		}
+		if tt.env == nil && cmd.Env != nil {
+			t.Errorf("%s: env = %q, want the inherited environment", tt.context, cmd.Env)
+		}
+		for _, kv := range tt.env {
+			if !slices.Contains(cmd.Env, kv) {
+				t.Errorf("%s: env lacks %s", tt.context, kv)
+			}
+		}
+		for _, kv := range cmd.Env {
+			if tt.unset != "" && strings.HasPrefix(kv, tt.unset) {
+				t.Errorf("%s: env has %s", tt.context, kv)
+			}
+		}
+	}
+}
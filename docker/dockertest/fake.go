@@ -0,0 +1,756 @@
+// Package dockertest provides an in-memory Docker API for tests.
+package dockertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gdocker/models"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+)
+
+// Container is a scripted container. IDs may be given in short form;
+// lookups match any prefix of the ID, as the daemon does.
+type Container struct {
+	ID      string
+	Name    string
+	Image   string
+	State   string // running, exited, ...
+	Project string // Compose project label
+	Service string // Compose service label
+	Created time.Time
+	Env     []string
+	TTY     bool
+	Ports   []container.PortSummary
+
+	ImageID  string   // Image the container runs, without "sha256:"
+	Volumes  []string // Named volumes mounted into the container
+	Networks []string // Networks the container is attached to
+
+	// Logs is the log history returned by ContainerLogs.
+	Logs []LogLine
+	// Stats are returned by ContainerStats: a stream sends them all, a
+	// single sample takes the next one and repeats the last.
+	Stats []container.StatsResponse
+}
+
+// LogLine is one line of scripted container output.
+type LogLine struct {
+	Time   time.Time
+	Stderr bool
+	Text   string
+}
+
+// Call records one API call made against the fake.
+type Call struct {
+	Method string
+	ID     string // Container, volume or image the call was about, if any
+}
+
+// Fake is an in-memory models.DockerAPI. Tests add containers, volumes,
+// images, networks and events, optionally make methods fail or hang, and check the
+// recorded calls afterwards. It is safe for concurrent use.
+type Fake struct {
+	mu         sync.Mutex
+	containers []*Container
+	volumes    []volume.Volume
+	images     []image.Summary
+	networks   []network.Summary
+	events     []events.Message
+	subs       []chan events.Message
+	errs       map[string]error
+	hung       map[string]bool
+	calls      []Call
+	down       bool
+}
+
+var _ models.DockerAPI = (*Fake)(nil)
+
+// New returns an empty fake daemon.
+func New() *Fake {
+	return &Fake{errs: make(map[string]error), hung: make(map[string]bool)}
+}
+
+// defaultAge is how long ago resources were created unless a test says
+// otherwise, so relative times render the same on every run.
+const defaultAge = 72 * time.Hour
+
+// AddContainer adds a container. Its state defaults to running.
+func (f *Fake) AddContainer(c Container) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c.State == "" {
+		c.State = "running"
+	}
+	if c.Created.IsZero() {
+		c.Created = time.Now().Add(-defaultAge).Truncate(time.Second)
+	}
+	f.containers = append(f.containers, &c)
+}
+
+// SetState changes a container's state, as if it was changed outside
+// gdocker.
+func (f *Fake) SetState(id, state string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c := f.lookup(id); c != nil {
+		c.State = state
+	}
+}
+
+// AppendLogs adds lines to the end of a container's log history, as if it
+// kept writing after its logs were read.
+func (f *Fake) AppendLogs(id string, lines ...LogLine) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c := f.lookup(id); c != nil {
+		c.Logs = append(c.Logs, lines...)
+	}
+}
+
+// AddVolume adds a volume.
+func (f *Fake) AddVolume(name, driver string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volumes = append(f.volumes, volume.Volume{
+		Name:       name,
+		Driver:     driver,
+		Scope:      "local",
+		Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+		CreatedAt:  time.Now().Add(-defaultAge).Format(time.RFC3339Nano),
+	})
+}
+
+// AddImage adds an image. id is the hex digest without the sha256: prefix.
+func (f *Fake) AddImage(id string, size int64, tags ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.images = append(f.images, image.Summary{ID: "sha256:" + id, RepoTags: tags, Size: size, Created: time.Now().Add(-defaultAge).Unix()})
+}
+
+// AddNetwork adds a network.
+func (f *Fake) AddNetwork(id, name, driver string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.networks = append(f.networks, network.Summary{Network: network.Network{
+		ID:      id,
+		Name:    name,
+		Driver:  driver,
+		Scope:   "local",
+		Created: time.Now().Add(-defaultAge),
+	}})
+}
+
+// Fail makes every later call of method return err. A nil err clears it.
+func (f *Fake) Fail(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// Hang makes later calls of method block until their context ends, like a
+// daemon that stopped answering, and fail with the context's error.
+// Hang(method, false) lets them through again.
+func (f *Fake) Hang(method string, hang bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hung[method] = hang
+}
+
+// hold blocks a call of a hung method until ctx ends.
+func (f *Fake) hold(ctx context.Context, method string) error {
+	f.mu.Lock()
+	hung := f.hung[method]
+	f.mu.Unlock()
+	if !hung {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// SetDown makes the daemon unreachable, or reachable again. While it is
+// down every call fails with the client's connection error and open event
+// subscriptions end.
+func (f *Fake) SetDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+	if down {
+		for _, sub := range f.subs {
+			close(sub)
+		}
+		f.subs = nil
+	}
+}
+
+// errUnreachable is the error the real client returns when no daemon
+// listens on its socket.
+var errUnreachable = sync.OnceValue(func() error {
+	cli, err := client.NewClientWithOpts(client.WithHost("unix:///nonexistent/docker.sock"))
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	_, err = cli.Ping(context.Background(), client.PingOptions{})
+	return err
+})
+
+// Emit records an event and delivers it to open event subscriptions.
+func (f *Fake) Emit(msg events.Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.emit(msg)
+}
+
+// Calls returns the recorded calls of method, or every call when method
+// is empty.
+func (f *Fake) Calls(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, c := range f.calls {
+		if method == "" || c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// record notes a call and returns the error scripted for it. The caller
+// holds f.mu.
+func (f *Fake) record(method, id string) error {
+	f.calls = append(f.calls, Call{Method: method, ID: id})
+	if f.down {
+		return errUnreachable()
+	}
+	return f.errs[method]
+}
+
+func (f *Fake) lookup(id string) *Container {
+	for _, c := range f.containers {
+		if id != "" && (strings.HasPrefix(c.ID, id) || c.Name == strings.TrimPrefix(id, "/")) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (f *Fake) emit(msg events.Message) {
+	if msg.TimeNano == 0 {
+		now := time.Now()
+		msg.Time, msg.TimeNano = now.Unix(), now.UnixNano()
+	}
+	f.events = append(f.events, msg)
+	for _, sub := range f.subs {
+		select {
+		case sub <- msg:
+		default:
+		}
+	}
+}
+
+func (f *Fake) emitContainer(c *Container, action events.Action) {
+	f.emit(events.Message{
+		Type:   events.ContainerEventType,
+		Action: action,
+		Actor:  events.Actor{ID: c.ID, Attributes: map[string]string{"name": c.Name, "image": c.Image}},
+	})
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("Error response from daemon: No such %s: %s", kind, id)
+}
+
+func (f *Fake) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	if err := f.hold(ctx, "ContainerList"); err != nil {
+		return client.ContainerListResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ContainerList", ""); err != nil {
+		return client.ContainerListResult{}, err
+	}
+
+	var result client.ContainerListResult
+	for _, c := range f.containers {
+		if !options.All && c.State != "running" {
+			continue
+		}
+		if ids := options.Filters["id"]; len(ids) > 0 && !slices.ContainsFunc(mapKeys(ids), func(id string) bool {
+			return strings.HasPrefix(c.ID, id)
+		}) {
+			continue
+		}
+
+		labels := make(map[string]string)
+		if c.Project != "" {
+			labels["com.docker.compose.project"] = c.Project
+		}
+		if c.Service != "" {
+			labels["com.docker.compose.service"] = c.Service
+		}
+		status := "Up 1 hour"
+		if c.State != "running" {
+			status = "Exited (0) 1 hour ago"
+		}
+		var mounts []container.MountPoint
+		for _, name := range c.Volumes {
+			mounts = append(mounts, container.MountPoint{Type: mount.TypeVolume, Name: name})
+		}
+		endpoints := make(map[string]*network.EndpointSettings)
+		for _, name := range c.Networks {
+			endpoints[name] = &network.EndpointSettings{}
+		}
+		result.Items = append(result.Items, container.Summary{
+			ID:              c.ID,
+			Names:           []string{"/" + c.Name},
+			Image:           c.Image,
+			ImageID:         "sha256:" + c.ImageID,
+			Created:         c.Created.Unix(),
+			Ports:           c.Ports,
+			Labels:          labels,
+			State:           container.ContainerState(c.State),
+			Status:          status,
+			Mounts:          mounts,
+			NetworkSettings: &container.NetworkSettingsSummary{Networks: endpoints},
+		})
+	}
+	return result, nil
+}
+
+func (f *Fake) ContainerInspect(ctx context.Context, containerID string, _ client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
+	if err := f.hold(ctx, "ContainerInspect"); err != nil {
+		return client.ContainerInspectResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ContainerInspect", containerID); err != nil {
+		return client.ContainerInspectResult{}, err
+	}
+	c := f.lookup(containerID)
+	if c == nil {
+		return client.ContainerInspectResult{}, notFound("container", containerID)
+	}
+
+	inspect := container.InspectResponse{
+		ID:      c.ID,
+		Name:    "/" + c.Name,
+		Image:   c.Image,
+		Created: c.Created.Format(time.RFC3339Nano),
+		State: &container.State{
+			Status:  container.ContainerState(c.State),
+			Running: c.State == "running",
+		},
+		Config: &container.Config{
+			Image: c.Image,
+			Env:   c.Env,
+			Tty:   c.TTY,
+		},
+	}
+	raw, _ := json.Marshal(inspect)
+	return client.ContainerInspectResult{Container: inspect, Raw: raw}, nil
+}
+
+// ContainerLogs serves the scripted log history within the options' tail,
+// since and until bounds, framed like the daemon does unless the container
+// has a TTY. Like the daemon, it takes the tail of the stream first and
+// applies since and until to that. A follow stream stays open until ctx is cancelled.
+func (f *Fake) ContainerLogs(ctx context.Context, containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+	if err := f.hold(ctx, "ContainerLogs"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	if err := f.record("ContainerLogs", containerID); err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	c := f.lookup(containerID)
+	if c == nil {
+		f.mu.Unlock()
+		return nil, notFound("container", containerID)
+	}
+	lines := slices.Clone(c.Logs)
+	tty := c.TTY
+	f.mu.Unlock()
+
+	since, err := parseTime(options.Since)
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseTime(options.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	var stream []LogLine
+	for _, line := range lines {
+		if line.Stderr && !options.ShowStderr || !line.Stderr && !options.ShowStdout {
+			continue
+		}
+		stream = append(stream, line)
+	}
+	if n, err := strconv.Atoi(options.Tail); err == nil && n < len(stream) {
+		stream = stream[len(stream)-n:]
+	}
+	var selected []LogLine
+	for _, line := range stream {
+		if !since.IsZero() && line.Time.Before(since) || !until.IsZero() && line.Time.After(until) {
+			continue
+		}
+		selected = append(selected, line)
+	}
+
+	var buf bytes.Buffer
+	for _, line := range selected {
+		text := line.Text
+		if options.Timestamps {
+			text = line.Time.UTC().Format(time.RFC3339Nano) + " " + text
+		}
+		text += "\n"
+		if tty {
+			buf.WriteString(text)
+			continue
+		}
+		header := make([]byte, 8)
+		header[0] = 1
+		if line.Stderr {
+			header[0] = 2
+		}
+		binary.BigEndian.PutUint32(header[4:], uint32(len(text)))
+		buf.Write(header)
+		buf.WriteString(text)
+	}
+
+	if !options.Follow {
+		return io.NopCloser(&buf), nil
+	}
+	return holdOpen(ctx, buf.Bytes()), nil
+}
+
+// ContainerStats encodes the scripted samples as the daemon's JSON stream.
+func (f *Fake) ContainerStats(ctx context.Context, containerID string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
+	if err := f.hold(ctx, "ContainerStats"); err != nil {
+		return client.ContainerStatsResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ContainerStats", containerID); err != nil {
+		return client.ContainerStatsResult{}, err
+	}
+	c := f.lookup(containerID)
+	if c == nil {
+		return client.ContainerStatsResult{}, notFound("container", containerID)
+	}
+	if len(c.Stats) == 0 {
+		return client.ContainerStatsResult{}, fmt.Errorf("no stats for container %s", containerID)
+	}
+
+	samples := c.Stats
+	if !options.Stream {
+		samples = c.Stats[:1]
+		if len(c.Stats) > 1 {
+			c.Stats = c.Stats[1:]
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range samples {
+		if err := enc.Encode(s); err != nil {
+			return client.ContainerStatsResult{}, err
+		}
+	}
+	return client.ContainerStatsResult{Body: io.NopCloser(&buf)}, nil
+}
+
+func (f *Fake) ContainerStart(ctx context.Context, containerID string, _ client.ContainerStartOptions) (client.ContainerStartResult, error) {
+	return client.ContainerStartResult{}, f.setContainerState(ctx, "ContainerStart", containerID, "running", "start")
+}
+
+func (f *Fake) ContainerStop(ctx context.Context, containerID string, _ client.ContainerStopOptions) (client.ContainerStopResult, error) {
+	return client.ContainerStopResult{}, f.setContainerState(ctx, "ContainerStop", containerID, "exited", "die")
+}
+
+func (f *Fake) ContainerRestart(ctx context.Context, containerID string, _ client.ContainerRestartOptions) (client.ContainerRestartResult, error) {
+	return client.ContainerRestartResult{}, f.setContainerState(ctx, "ContainerRestart", containerID, "running", "restart")
+}
+
+func (f *Fake) setContainerState(ctx context.Context, method, id, state string, action events.Action) error {
+	if err := f.hold(ctx, method); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method, id); err != nil {
+		return err
+	}
+	c := f.lookup(id)
+	if c == nil {
+		return notFound("container", id)
+	}
+	c.State = state
+	f.emitContainer(c, action)
+	return nil
+}
+
+func (f *Fake) ContainerRemove(ctx context.Context, containerID string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	if err := f.hold(ctx, "ContainerRemove"); err != nil {
+		return client.ContainerRemoveResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ContainerRemove", containerID); err != nil {
+		return client.ContainerRemoveResult{}, err
+	}
+	c := f.lookup(containerID)
+	if c == nil {
+		return client.ContainerRemoveResult{}, notFound("container", containerID)
+	}
+	if c.State == "running" && !options.Force {
+		return client.ContainerRemoveResult{}, fmt.Errorf("Error response from daemon: cannot remove container %s: container is running", c.Name)
+	}
+	f.containers = slices.DeleteFunc(f.containers, func(other *Container) bool { return other == c })
+	f.emitContainer(c, events.ActionDestroy)
+	return client.ContainerRemoveResult{}, nil
+}
+
+func (f *Fake) VolumeList(ctx context.Context, _ client.VolumeListOptions) (client.VolumeListResult, error) {
+	if err := f.hold(ctx, "VolumeList"); err != nil {
+		return client.VolumeListResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("VolumeList", ""); err != nil {
+		return client.VolumeListResult{}, err
+	}
+	return client.VolumeListResult{Items: slices.Clone(f.volumes)}, nil
+}
+
+func (f *Fake) VolumeRemove(ctx context.Context, volumeID string, _ client.VolumeRemoveOptions) (client.VolumeRemoveResult, error) {
+	if err := f.hold(ctx, "VolumeRemove"); err != nil {
+		return client.VolumeRemoveResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("VolumeRemove", volumeID); err != nil {
+		return client.VolumeRemoveResult{}, err
+	}
+	n := len(f.volumes)
+	f.volumes = slices.DeleteFunc(f.volumes, func(v volume.Volume) bool { return v.Name == volumeID })
+	if len(f.volumes) == n {
+		return client.VolumeRemoveResult{}, notFound("volume", volumeID)
+	}
+	return client.VolumeRemoveResult{}, nil
+}
+
+func (f *Fake) ImageList(ctx context.Context, _ client.ImageListOptions) (client.ImageListResult, error) {
+	if err := f.hold(ctx, "ImageList"); err != nil {
+		return client.ImageListResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ImageList", ""); err != nil {
+		return client.ImageListResult{}, err
+	}
+	return client.ImageListResult{Items: slices.Clone(f.images)}, nil
+}
+
+func (f *Fake) ImageRemove(ctx context.Context, imageID string, _ client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	if err := f.hold(ctx, "ImageRemove"); err != nil {
+		return client.ImageRemoveResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ImageRemove", imageID); err != nil {
+		return client.ImageRemoveResult{}, err
+	}
+	n := len(f.images)
+	f.images = slices.DeleteFunc(f.images, func(img image.Summary) bool {
+		return strings.HasPrefix(strings.TrimPrefix(img.ID, "sha256:"), imageID) || slices.Contains(img.RepoTags, imageID)
+	})
+	if len(f.images) == n {
+		return client.ImageRemoveResult{}, notFound("image", imageID)
+	}
+	return client.ImageRemoveResult{}, nil
+}
+
+func (f *Fake) NetworkList(ctx context.Context, _ client.NetworkListOptions) (client.NetworkListResult, error) {
+	if err := f.hold(ctx, "NetworkList"); err != nil {
+		return client.NetworkListResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("NetworkList", ""); err != nil {
+		return client.NetworkListResult{}, err
+	}
+	return client.NetworkListResult{Items: slices.Clone(f.networks)}, nil
+}
+
+func (f *Fake) NetworkRemove(ctx context.Context, networkID string, _ client.NetworkRemoveOptions) (client.NetworkRemoveResult, error) {
+	if err := f.hold(ctx, "NetworkRemove"); err != nil {
+		return client.NetworkRemoveResult{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("NetworkRemove", networkID); err != nil {
+		return client.NetworkRemoveResult{}, err
+	}
+	n := len(f.networks)
+	f.networks = slices.DeleteFunc(f.networks, func(nw network.Summary) bool {
+		return strings.HasPrefix(nw.ID, networkID) || nw.Name == networkID
+	})
+	if len(f.networks) == n {
+		return client.NetworkRemoveResult{}, notFound("network", networkID)
+	}
+	return client.NetworkRemoveResult{}, nil
+}
+
+// Events replays the recorded events within since and until. Without until
+// the subscription stays open and delivers emitted events until ctx is
+// cancelled; with until it ends with io.EOF, like the daemon.
+func (f *Fake) Events(ctx context.Context, options client.EventsListOptions) client.EventsResult {
+	messages := make(chan events.Message, 64)
+	errs := make(chan error, 1)
+	result := client.EventsResult{Messages: messages, Err: errs}
+
+	f.mu.Lock()
+	if err := f.record("Events", ""); err != nil {
+		f.mu.Unlock()
+		errs <- err
+		return result
+	}
+	since, err := parseTime(options.Since)
+	if err == nil {
+		var until time.Time
+		until, err = parseTime(options.Until)
+		if err == nil {
+			var history []events.Message
+			for _, msg := range f.events {
+				t := time.Unix(0, msg.TimeNano)
+				if !since.IsZero() && t.Before(since) || !until.IsZero() && t.After(until) {
+					continue
+				}
+				history = append(history, msg)
+			}
+			var live chan events.Message
+			if until.IsZero() {
+				live = make(chan events.Message, 64)
+				f.subs = append(f.subs, live)
+			}
+			go f.serveEvents(ctx, history, live, options.Until != "", messages, errs)
+		}
+	}
+	f.mu.Unlock()
+	if err != nil {
+		errs <- err
+	}
+	return result
+}
+
+func (f *Fake) serveEvents(ctx context.Context, history []events.Message, live chan events.Message, bounded bool, messages chan<- events.Message, errs chan<- error) {
+	defer func() {
+		if live == nil {
+			return
+		}
+		f.mu.Lock()
+		f.subs = slices.DeleteFunc(f.subs, func(c chan events.Message) bool { return c == live })
+		f.mu.Unlock()
+	}()
+
+	for _, msg := range history {
+		select {
+		case messages <- msg:
+		case <-ctx.Done():
+			errs <- ctx.Err()
+			return
+		}
+	}
+	if bounded {
+		errs <- io.EOF
+		return
+	}
+	for {
+		select {
+		case msg, ok := <-live:
+			if !ok {
+				errs <- errUnreachable()
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		case <-ctx.Done():
+			errs <- ctx.Err()
+			return
+		}
+	}
+}
+
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record("Close", "")
+}
+
+// parseTime reads the since/until values gdocker sends: a duration before
+// now, an RFC3339 timestamp or Unix seconds.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(0, int64(secs*float64(time.Second))), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// holdOpen returns a reader that yields data and then blocks until ctx is
+// cancelled, like a follow stream with no new output.
+func holdOpen(ctx context.Context, data []byte) io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		if _, err := w.Write(data); err != nil {
+			return
+		}
+		<-ctx.Done()
+		w.CloseWithError(ctx.Err())
+	}()
+	return r
+}
+
+func mapKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
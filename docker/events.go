@@ -0,0 +1,210 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gdocker/models"
+	"io"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// eventStreamBuffer is how many events may queue before the reader waits
+// for the UI.
+const eventStreamBuffer = 256
+
+// SubscribeEvents opens a new daemon event subscription and stores it on
+// the model. It resumes after the last event received, so a reconnect
+// replays what happened while the stream was down.
+func SubscribeEvents(m *models.Model) tea.Cmd {
+	m.EventStream = openEventStream(m.DockerClient, m.LastEventTime)
+	return m.EventStream.Next()
+}
+
+// openEventStream subscribes to daemon events. A non-zero since replays the
+// events after that point before the live ones.
+func openEventStream(cli models.DockerAPI, since time.Time) *models.EventStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan models.DockerEvent, eventStreamBuffer)
+	done := make(chan error, 1)
+	stream := &models.EventStream{Events: out, Done: done, Cancel: cancel}
+
+	opts := client.EventsListOptions{}
+	if !since.IsZero() {
+		// The daemon includes events at since itself, which were seen
+		opts.Since = since.Add(time.Nanosecond).Format(time.RFC3339Nano)
+	}
+
+	go func() {
+		defer close(out)
+		defer close(done)
+		// Events blocks until the daemon answers, so it runs here rather
+		// than on the caller's goroutine.
+		result := cli.Events(ctx, opts)
+		for {
+			select {
+			case msg := <-result.Messages:
+				select {
+				case out <- toDockerEvent(msg):
+				case <-ctx.Done():
+					return
+				}
+			case err := <-result.Err:
+				if ctx.Err() == nil {
+					done <- err
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return stream
+}
+
+func toDockerEvent(msg events.Message) models.DockerEvent {
+	event := models.DockerEvent{
+		Time:       time.Unix(0, msg.TimeNano),
+		Type:       string(msg.Type),
+		Action:     string(msg.Action),
+		ActorID:    msg.Actor.ID,
+		Attributes: msg.Actor.Attributes,
+	}
+	if msg.TimeNano == 0 {
+		event.Time = time.Unix(msg.Time, 0)
+	}
+	return event
+}
+
+// LoadEventHistory replays the daemon events from since up to now for the
+// events timeline. since is a duration such as 1h or an RFC3339 timestamp.
+func LoadEventHistory(m *models.Model, since string) tea.Cmd {
+	cli := m.DockerClient
+	timeout := m.TimeoutSettings().List
+	return m.StartOperation("Loading events since "+since, timeout, func(ctx context.Context) tea.Msg {
+		until := time.Now()
+		result := cli.Events(ctx, client.EventsListOptions{
+			Since: since,
+			Until: strconv.FormatInt(until.Unix(), 10),
+		})
+		var history []models.DockerEvent
+		for {
+			select {
+			case msg := <-result.Messages:
+				history = append(history, toDockerEvent(msg))
+			case err := <-result.Err:
+				// The daemon closes the stream once it reaches until.
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				return models.EventHistoryLoadedMsg{Since: since, Until: until, Events: history, Err: ctxError(ctx, err, timeout)}
+			}
+		}
+	})
+}
+
+// RebuildEventItems lists the events that pass the timeline filter, oldest
+// first. A cursor on the last event follows new ones as they arrive.
+func RebuildEventItems(m *models.Model) {
+	following := m.Cursor >= len(m.Items)-1
+
+	m.Items = []models.ListItem{}
+	for i := range m.EventLog {
+		if models.EventMatches(m, m.EventLog[i]) {
+			m.Items = append(m.Items, models.ListItem{
+				IsEvent: true,
+				Event:   &m.EventLog[i],
+				Index:   i,
+			})
+		}
+	}
+
+	if following {
+		m.Cursor = len(m.Items) - 1
+	}
+	m.Cursor = min(max(m.Cursor, 0), max(len(m.Items)-1, 0))
+}
+
+// loadFailed reports a failed list load, telling an unreachable daemon
+// apart from one that rejected the request. A daemon that does not answer
+// within the timeout counts as unreachable.
+func loadFailed(cli models.DockerAPI, r models.Resource, err error) tea.Msg {
+	var timedOut timeoutError
+	unreachable := client.IsErrConnectionFailed(err) || errors.As(err, &timedOut)
+	return models.ResourceFailedMsg{Client: cli, Resource: r, Err: err, Unreachable: unreachable}
+}
+
+// LoadContainer fetches the current state of one container after an event.
+func LoadContainer(m *models.Model, id string) tea.Cmd {
+	cli := m.DockerClient
+	env := m.EnvCache
+	timeout := m.TimeoutSettings().List
+	return func() tea.Msg {
+		ctx, cancel := withTimeout(context.Background(), timeout)
+		defer cancel()
+		containers, err := listContainers(ctx, cli, env, client.ContainerListOptions{
+			All:     true,
+			Filters: make(client.Filters).Add("id", id),
+		})
+		if err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload container: %v", ctxError(ctx, err, timeout)), Success: false}
+		}
+		for _, c := range containers {
+			if c.ID == id {
+				return models.ContainerUpdatedMsg{Client: cli, ID: id, Container: &c}
+			}
+		}
+		return models.ContainerUpdatedMsg{Client: cli, ID: id}
+	}
+}
+
+// ReloadVolumes lists the volumes again after a volume event.
+func ReloadVolumes(m *models.Model) tea.Cmd {
+	cli := m.DockerClient
+	timeout := m.TimeoutSettings().List
+	return func() tea.Msg {
+		ctx, cancel := withTimeout(context.Background(), timeout)
+		defer cancel()
+		volumes, err := listVolumes(ctx, cli)
+		if err != nil {
+			return loadFailed(cli, models.ResourceVolumes, ctxError(ctx, err, timeout))
+		}
+		return models.VolumesLoadedMsg{Client: cli, Volumes: volumes}
+	}
+}
+
+// ReloadImages lists the images again after an image event.
+func ReloadImages(m *models.Model) tea.Cmd {
+	cli := m.DockerClient
+	timeout := m.TimeoutSettings().List
+	return func() tea.Msg {
+		ctx, cancel := withTimeout(context.Background(), timeout)
+		defer cancel()
+		images, err := listImages(ctx, cli)
+		if err != nil {
+			return loadFailed(cli, models.ResourceImages, ctxError(ctx, err, timeout))
+		}
+		return models.ImagesLoadedMsg{Client: cli, Images: images}
+	}
+}
+
+// ReloadNetworks lists the networks again after a network event.
+func ReloadNetworks(m *models.Model) tea.Cmd {
+	cli := m.DockerClient
+	timeout := m.TimeoutSettings().List
+	return func() tea.Msg {
+		ctx, cancel := withTimeout(context.Background(), timeout)
+		defer cancel()
+		networks, err := listNetworks(ctx, cli)
+		if err != nil {
+			return loadFailed(cli, models.ResourceNetworks, ctxError(ctx, err, timeout))
+		}
+		return models.NetworksLoadedMsg{Client: cli, Networks: networks}
+	}
+}
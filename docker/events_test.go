@@ -0,0 +1,29 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"gdocker/docker/dockertest"
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCancelledEventStreamEnds(t *testing.T) {
+	stream := openEventStream(dockertest.New(), time.Time{})
+	next := stream.Next()
+	stream.Cancel()
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- next() }()
+	select {
+	case msg := <-done:
+		ended, ok := msg.(models.EventStreamEndedMsg)
+		if !ok || ended.Stream != stream || ended.Err != nil {
+			t.Errorf("Next = %+v, want the end of the stream without an error", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Next still blocks after the stream was cancelled")
+	}
+}
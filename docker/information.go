@@ -3,63 +3,131 @@ package docker
 import (
 	"context"
 	"gdocker/models"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
 	"github.com/moby/moby/client"
 )
 
-func LoadContainers(m *models.Model) error {
-	containerList, err := m.DockerClient.ContainerList(context.Background(), client.ContainerListOptions{All: true})
+// inspectParallelism bounds how many containers are inspected at once, so
+// a refresh against a remote host does not open a connection per container.
+const inspectParallelism = 8
+
+// loadAllContainers lists every container, running or not. It backs both
+// the initial load and later refreshes.
+func loadAllContainers(ctx context.Context, cli models.DockerAPI, env *models.EnvCache) ([]models.Container, error) {
+	containers, err := listContainers(ctx, cli, env, client.ContainerListOptions{All: true})
 	if err != nil {
-		return err
-	}
-	// Parse containers
-	var containers []models.Container
-	for _, c := range containerList.Items {
-		name := strings.TrimPrefix(c.Names[0], "/")
-		project := c.Labels["com.docker.compose.project"]
-
-		// Parse ports
-		var ports []models.PortMapping
-		for _, p := range c.Ports {
-			ip := ""
-			if p.IP.IsValid() {
-				ip = p.IP.String()
-			}
-			ports = append(ports, models.PortMapping{
-				PrivatePort: p.PrivatePort,
-				PublicPort:  p.PublicPort,
-				Type:        p.Type,
-				IP:          ip,
-			})
+		return nil, err
+	}
+	env.Retain(containers)
+	return containers, nil
+}
+
+// listContainers lists and parses the containers selected by opts.
+func listContainers(ctx context.Context, cli models.DockerAPI, env *models.EnvCache, opts client.ContainerListOptions) ([]models.Container, error) {
+	containerList, err := cli.ContainerList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]models.Container, len(containerList.Items))
+	for i, c := range containerList.Items {
+		containers[i] = parseContainer(c)
+	}
+	loadContainerEnv(ctx, cli, env, containerList.Items, containers)
+	return containers, nil
+}
+
+func parseContainer(c container.Summary) models.Container {
+	// Parse ports
+	var ports []models.PortMapping
+	for _, p := range c.Ports {
+		ip := ""
+		if p.IP.IsValid() {
+			ip = p.IP.String()
 		}
+		ports = append(ports, models.PortMapping{
+			PrivatePort: p.PrivatePort,
+			PublicPort:  p.PublicPort,
+			Type:        p.Type,
+			IP:          ip,
+		})
+	}
 
-		// Get environment variables by inspecting the container
-		var env []string
-		if inspect, err := m.DockerClient.ContainerInspect(context.Background(), c.ID, client.ContainerInspectOptions{}); err == nil {
-			env = inspect.Container.Config.Env
+	// Volumes and networks in use tell what a delete would affect
+	var volumes, networks []string
+	for _, mp := range c.Mounts {
+		if mp.Type == mount.TypeVolume {
+			volumes = append(volumes, mp.Name)
 		}
+	}
+	if c.NetworkSettings != nil {
+		for name := range c.NetworkSettings.Networks {
+			networks = append(networks, name)
+		}
+		sort.Strings(networks)
+	}
 
-		containers = append(containers, models.Container{
-			ID:      c.ID[:12],
-			Name:    name,
-			Image:   c.Image,
-			State:   string(c.State),
-			Status:  c.Status,
-			Project: project,
-			Created: time.Unix(c.Created, 0),
-			Ports:   ports,
-			Env:     env,
-		})
+	return models.Container{
+		ID:       c.ID[:12],
+		Name:     strings.TrimPrefix(c.Names[0], "/"),
+		Image:    c.Image,
+		State:    string(c.State),
+		Status:   c.Status,
+		Project:  c.Labels["com.docker.compose.project"],
+		Service:  c.Labels["com.docker.compose.service"],
+		Created:  time.Unix(c.Created, 0),
+		Ports:    ports,
+		ImageID:  shortImageID(c.ImageID),
+		Volumes:  volumes,
+		Networks: networks,
 	}
+}
+
+// shortImageID strips the digest algorithm from an image ID and shortens
+// it to the 12 characters shown in the images list.
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
 
-	// Group by compose projects
-	m.Containers = containers
-	m.Standalone, m.Projects = GroupByProject(containers)
-	RebuildItems(m)
+// loadContainerEnv fills in the environment variables, which only
+// ContainerInspect reports. Values cached in env are reused; the remaining
+// containers are inspected at most inspectParallelism at a time. A failed
+// inspect leaves the environment empty and is retried on the next refresh.
+func loadContainerEnv(ctx context.Context, cli models.DockerAPI, env *models.EnvCache, summaries []container.Summary, containers []models.Container) {
+	sem := make(chan struct{}, inspectParallelism)
+	var wg sync.WaitGroup
+	for i, c := range summaries {
+		state := string(c.State)
+		if cached, ok := env.Get(containers[i].ID, c.Created, state); ok {
+			containers[i].Env = cached
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-	return nil
+			inspect, err := cli.ContainerInspect(ctx, c.ID, client.ContainerInspectOptions{})
+			if err != nil || inspect.Container.Config == nil {
+				return
+			}
+			containers[i].Env = inspect.Container.Config.Env
+			env.Put(containers[i].ID, c.Created, state, containers[i].Env)
+		}()
+	}
+	wg.Wait()
 }
 
 func GroupByProject(containers []models.Container) ([]models.Container, []models.ComposeGroup) {
@@ -82,6 +150,10 @@ func GroupByProject(containers []models.Container) ([]models.Container, []models
 			Expanded:   false,
 		})
 	}
+	// Map order is random; keep projects in a stable order across refreshes
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
 
 	return standalone, projects
 }
@@ -125,10 +197,10 @@ func RebuildItems(m *models.Model) {
 	}
 }
 
-func LoadVolumes(m *models.Model) error {
-	volumeList, err := m.DockerClient.VolumeList(context.Background(), client.VolumeListOptions{})
+func listVolumes(ctx context.Context, cli models.DockerAPI) ([]models.Volume, error) {
+	volumeList, err := cli.VolumeList(ctx, client.VolumeListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var volumes []models.Volume
@@ -150,15 +222,13 @@ func LoadVolumes(m *models.Model) error {
 			Scope:      v.Scope,
 		})
 	}
-
-	m.Volumes = volumes
-	return nil
+	return volumes, nil
 }
 
-func LoadImages(m *models.Model) error {
-	imageList, err := m.DockerClient.ImageList(context.Background(), client.ImageListOptions{All: true})
+func listImages(ctx context.Context, cli models.DockerAPI) ([]models.Image, error) {
+	imageList, err := cli.ImageList(ctx, client.ImageListOptions{All: true})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var images []models.Image
@@ -170,9 +240,7 @@ func LoadImages(m *models.Model) error {
 			Created:  time.Unix(img.Created, 0),
 		})
 	}
-
-	m.Images = images
-	return nil
+	return images, nil
 }
 
 func RebuildVolumeItems(m *models.Model) {
@@ -215,10 +283,10 @@ func RebuildImageItems(m *models.Model) {
 	}
 }
 
-func LoadNetworks(m *models.Model) error {
-	networkList, err := m.DockerClient.NetworkList(context.Background(), client.NetworkListOptions{})
+func listNetworks(ctx context.Context, cli models.DockerAPI) ([]models.Network, error) {
+	networkList, err := cli.NetworkList(ctx, client.NetworkListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var networks []models.Network
@@ -233,9 +301,7 @@ func LoadNetworks(m *models.Model) error {
 			Labels:   n.Labels,
 		})
 	}
-
-	m.Networks = networks
-	return nil
+	return networks, nil
 }
 
 func RebuildNetworkItems(m *models.Model) {
@@ -257,3 +323,50 @@ func RebuildNetworkItems(m *models.Model) {
 		m.Cursor = 0
 	}
 }
+
+// RebuildTopItems lists the running containers for the overview, sorted by
+// the selected column. The cursor stays on the same container.
+func RebuildTopItems(m *models.Model) {
+	selected := ""
+	if m.Cursor >= 0 && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
+		selected = m.Items[m.Cursor].Container.ID
+	}
+
+	m.Items = []models.ListItem{}
+	for i := range m.Containers {
+		if m.Containers[i].State == "running" {
+			m.Items = append(m.Items, models.ListItem{
+				IsContainer: true,
+				Container:   &m.Containers[i],
+				Index:       i,
+			})
+		}
+	}
+
+	sort.SliceStable(m.Items, func(i, j int) bool {
+		a, b := m.Items[i].Container, m.Items[j].Container
+		if m.TopSort == models.TopColumnName {
+			if m.TopSortAsc {
+				return a.Name < b.Name
+			}
+			return a.Name > b.Name
+		}
+		va := models.TopSortValue(m.TopStats[a.ID], m.TopSort)
+		vb := models.TopSortValue(m.TopStats[b.ID], m.TopSort)
+		if va == vb {
+			return a.Name < b.Name
+		}
+		if m.TopSortAsc {
+			return va < vb
+		}
+		return va > vb
+	})
+
+	m.Cursor = min(max(m.Cursor, 0), max(len(m.Items)-1, 0))
+	for i, item := range m.Items {
+		if item.Container.ID == selected {
+			m.Cursor = i
+			break
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gdocker/docker/dockertest"
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// newTestModel returns a model backed by fake.
+func newTestModel(t *testing.T, fake *dockertest.Fake) *models.Model {
+	t.Helper()
+	m := models.NewModel(fake, Services{}, nil)
+	return &m
+}
+
+// load runs the given list loaders and applies their results to m, as the
+// Bubble Tea runtime does.
+func load(t *testing.T, m *models.Model, loaders ...func(*models.Model) tea.Cmd) {
+	t.Helper()
+	for _, loader := range loaders {
+		msg := loader(m)()
+		if failed, ok := msg.(models.ResourceFailedMsg); ok {
+			t.Fatalf("loading %s: %v", failed.Resource, failed.Err)
+		}
+		next, _ := m.Update(msg)
+		*m = next.(models.Model)
+	}
+}
+
+func TestLoadContainersGroupsComposeProjects(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa0001", Name: "nginx", Image: "nginx"})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb0002", Name: "shop-api-1", Project: "shop", Service: "api"})
+	fake.AddContainer(dockertest.Container{ID: "cccccccccccc0003", Name: "shop-db-1", Project: "shop", Service: "db", State: "exited"})
+	m := newTestModel(t, fake)
+
+	load(t, m, RefreshContainers)
+
+	if len(m.Containers) != 3 {
+		t.Fatalf("got %d containers, want 3", len(m.Containers))
+	}
+	if len(m.Standalone) != 1 || m.Standalone[0].Name != "nginx" {
+		t.Errorf("standalone = %+v, want nginx only", m.Standalone)
+	}
+	if len(m.Projects) != 1 || m.Projects[0].Name != "shop" || len(m.Projects[0].Containers) != 2 {
+		t.Fatalf("projects = %+v, want shop with 2 containers", m.Projects)
+	}
+	if got := m.Containers[0].ID; got != "aaaaaaaaaaaa" {
+		t.Errorf("ID = %q, want the 12-character short form", got)
+	}
+	if got := m.Projects[0].Containers[0].Service; got != "api" {
+		t.Errorf("Service = %q, want api", got)
+	}
+	// One standalone row and one collapsed project row
+	if len(m.Items) != 2 || !m.Items[0].IsContainer || !m.Items[1].IsProject {
+		t.Errorf("items = %+v, want a container then a project", m.Items)
+	}
+}
+
+func TestLoadContainersError(t *testing.T) {
+	fake := dockertest.New()
+	fake.Fail("ContainerList", errors.New("daemon unreachable"))
+	m := newTestModel(t, fake)
+
+	msg, ok := RefreshContainers(m)().(models.ResourceFailedMsg)
+	if !ok || msg.Resource != models.ResourceContainers || msg.Err == nil {
+		t.Fatalf("msg = %+v, want the container list error", msg)
+	}
+}
+
+func TestRefreshInspectsOnlyChangedContainers(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Env: []string{"PORT=80"}})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "worker", Env: []string{"QUEUE=jobs"}})
+	m := newTestModel(t, fake)
+
+	load(t, m, RefreshContainers)
+	if n := len(fake.Calls("ContainerInspect")); n != 2 {
+		t.Fatalf("initial load inspected %d containers, want 2", n)
+	}
+	if got := m.Containers[0].Env; len(got) != 1 || got[0] != "PORT=80" {
+		t.Errorf("Env = %v, want [PORT=80]", got)
+	}
+
+	// Nothing changed: the environment comes from the cache
+	msg := RefreshContainers(m)()
+	refreshed, ok := msg.(models.ContainersRefreshedMsg)
+	if !ok {
+		t.Fatalf("RefreshContainers returned %T, want ContainersRefreshedMsg", msg)
+	}
+	if n := len(fake.Calls("ContainerInspect")); n != 2 {
+		t.Errorf("unchanged refresh inspected again: %d calls, want 2", n)
+	}
+	if got := refreshed.Containers[1].Env; len(got) != 1 || got[0] != "QUEUE=jobs" {
+		t.Errorf("cached Env = %v, want [QUEUE=jobs]", got)
+	}
+
+	// A state change re-inspects that container only
+	fake.SetState("bbbbbbbbbbbb", "exited")
+	RefreshContainers(m)()
+	calls := fake.Calls("ContainerInspect")
+	if len(calls) != 3 || calls[2].ID != "bbbbbbbbbbbb" {
+		t.Errorf("calls after state change = %+v, want one more for worker", calls)
+	}
+}
+
+func TestEnvCacheBelongsToTheModel(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	local, remote := dockertest.New(), dockertest.New()
+	local.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Created: created, Env: []string{"REGION=local"}})
+	remote.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Created: created, Env: []string{"REGION=eu"}})
+
+	// Same ID, creation time and state on two daemons
+	for _, tt := range []struct {
+		fake *dockertest.Fake
+		want string
+	}{{local, "REGION=local"}, {remote, "REGION=eu"}} {
+		m := newTestModel(t, tt.fake)
+		msg := RefreshContainers(m)().(models.ContainersRefreshedMsg)
+		if got := msg.Containers[0].Env; len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Env = %v, want [%s]", got, tt.want)
+		}
+	}
+	if len(local.Calls("ContainerInspect")) != 1 || len(remote.Calls("ContainerInspect")) != 1 {
+		t.Error("each daemon should be inspected once")
+	}
+}
+
+func TestEnvCacheDropsRemovedContainers(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", State: "exited", Created: time.Unix(1700000000, 0), Env: []string{"V=1"}})
+	m := newTestModel(t, fake)
+	RefreshContainers(m)()
+
+	// Recreated under the same ID and creation time, which the daemon
+	// would not do, so only the removal can have invalidated the entry
+	if _, err := fake.ContainerRemove(context.Background(), "aaaaaaaaaaaa", client.ContainerRemoveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	RefreshContainers(m)()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", State: "exited", Created: time.Unix(1700000000, 0), Env: []string{"V=2"}})
+
+	msg := RefreshContainers(m)().(models.ContainersRefreshedMsg)
+	if got := msg.Containers[0].Env; len(got) != 1 || got[0] != "V=2" {
+		t.Errorf("Env = %v, want [V=2] from a new inspect", got)
+	}
+	if n := len(fake.Calls("ContainerInspect")); n != 2 {
+		t.Errorf("%d inspects, want 2", n)
+	}
+}
+
+func TestListVolumesImagesNetworks(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddVolume("data", "local")
+	fake.AddImage("0123456789abcdef0123456789abcdef", 1024, "nginx:latest")
+	fake.AddNetwork("fedcba9876543210", "backend", "bridge")
+	m := newTestModel(t, fake)
+
+	load(t, m, ReloadVolumes)
+	load(t, m, ReloadImages)
+	load(t, m, ReloadNetworks)
+
+	if len(m.Volumes) != 1 || m.Volumes[0].Name != "data" || m.Volumes[0].Driver != "local" {
+		t.Errorf("volumes = %+v", m.Volumes)
+	}
+	if len(m.Images) != 1 || m.Images[0].ID != "0123456789ab" || m.Images[0].RepoTags[0] != "nginx:latest" {
+		t.Errorf("images = %+v", m.Images)
+	}
+	if len(m.Networks) != 1 || m.Networks[0].ID != "fedcba987654" || m.Networks[0].Name != "backend" {
+		t.Errorf("networks = %+v", m.Networks)
+	}
+}
+
+func TestLoadContainerAfterEvent(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web"})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "worker", Created: time.Unix(1800000000, 0)})
+	m := newTestModel(t, fake)
+
+	msg, ok := LoadContainer(m, "bbbbbbbbbbbb")().(models.ContainerUpdatedMsg)
+	if !ok || msg.Container == nil || msg.Container.Name != "worker" {
+		t.Fatalf("LoadContainer = %+v, want worker", msg)
+	}
+
+	msg, ok = LoadContainer(m, "cccccccccccc")().(models.ContainerUpdatedMsg)
+	if !ok || msg.Container != nil {
+		t.Errorf("LoadContainer of a removed container = %+v, want nil Container", msg)
+	}
+}
+
+func TestLoadFailuresTellUnreachableApart(t *testing.T) {
+	fake := dockertest.New()
+	m := newTestModel(t, fake)
+
+	fake.Fail("VolumeList", errors.New("permission denied"))
+	msg, ok := ReloadVolumes(m)().(models.ResourceFailedMsg)
+	if !ok || msg.Resource != models.ResourceVolumes || msg.Unreachable {
+		t.Errorf("ReloadVolumes = %+v, want a reachable volumes failure", msg)
+	}
+
+	fake.SetDown(true)
+	msg, ok = RefreshContainers(m)().(models.ResourceFailedMsg)
+	if !ok || msg.Resource != models.ResourceContainers || !msg.Unreachable {
+		t.Errorf("RefreshContainers = %+v, want an unreachable containers failure", msg)
+	}
+}
@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"gdocker/models"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// exportProgressInterval throttles progress updates from a running export.
+const exportProgressInterval = 250 * time.Millisecond
+
+// logFile is a log output file, gzip-compressed when its name ends in .gz.
+type logFile struct {
+	file  *os.File
+	gz    *gzip.Writer
+	buf   *bufio.Writer
+	bytes int64
+}
+
+// createLogFile creates path, expanding a leading "~/" to the home directory.
+func createLogFile(path string) (*logFile, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &logFile{file: file}
+	var w io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		f.gz = gzip.NewWriter(file)
+		w = f.gz
+	}
+	f.buf = bufio.NewWriter(w)
+	return f, nil
+}
+
+// writeEntry writes one line. Project logs keep their service prefix so the
+// file reads like docker compose logs.
+func (f *logFile) writeEntry(entry models.LogEntry, project, timestamps bool) error {
+	line := entry.Line
+	if !timestamps {
+		line = entry.Message()
+	}
+	if project {
+		line = entry.Source + " | " + line
+	}
+	n, err := f.buf.WriteString(line + "\n")
+	f.bytes += int64(n)
+	return err
+}
+
+// Close flushes and closes the file, returning the first error.
+func (f *logFile) Close() error {
+	err := f.buf.Flush()
+	if f.gz != nil {
+		if gzErr := f.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SaveLogs writes the lines currently shown in the log view to path.
+func SaveLogs(m *models.Model, path string, timestamps bool) tea.Cmd {
+	entries := make([]models.LogEntry, len(m.VisibleLogs))
+	for i, idx := range m.VisibleLogs {
+		entries[i] = m.Logs[idx]
+	}
+	project := m.LogProject != ""
+
+	return func() tea.Msg {
+		f, err := createLogFile(path)
+		if err != nil {
+			return models.LogsSavedMsg{Path: path, Err: err}
+		}
+		for _, entry := range entries {
+			if err = f.writeEntry(entry, project, timestamps); err != nil {
+				break
+			}
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return models.LogsSavedMsg{Path: path, Lines: len(entries), Err: err}
+	}
+}
+
+// ExportLogs streams the complete log history of every log target into path
+// in the background, merging project containers by timestamp. Progress is
+// reported through the LogExport stored on the model, as is a file that
+// cannot be created.
+func ExportLogs(m *models.Model, path string, timestamps bool) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan models.LogExportProgress, 1)
+	export := &models.LogExport{Path: path, Updates: updates, Cancel: cancel}
+	m.LogExport = export
+
+	cli := m.DockerClient
+	levels := m.LevelClassifier
+	targets := append([]models.LogTarget(nil), m.LogTargets...)
+	project := m.LogProject != ""
+
+	go func() {
+		defer close(updates)
+		defer cancel()
+
+		var progress models.LogExportProgress
+		f, err := createLogFile(path)
+		if err != nil {
+			progress.Done = true
+			progress.Err = err
+			updates <- progress
+			return
+		}
+
+		lastUpdate := time.Now()
+		err = streamLogHistory(ctx, cli, targets, levels, func(entry models.LogEntry) error {
+			if err := f.writeEntry(entry, project, timestamps); err != nil {
+				return err
+			}
+			progress.Lines++
+			progress.Bytes = f.bytes
+			if time.Since(lastUpdate) >= exportProgressInterval {
+				lastUpdate = time.Now()
+				// Drop the update if the UI has not read the previous one yet
+				select {
+				case updates <- progress:
+				default:
+				}
+			}
+			return nil
+		})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		progress.Bytes = f.bytes
+		progress.Done = true
+		progress.Err = err
+		// The final update must not be dropped; a stale progress update may
+		// still sit in the buffer until the UI reads it.
+		updates <- progress
+	}()
+
+	return export.Next()
+}
+
+// streamLogHistory reads the whole history of every target and calls fn for
+// each line in timestamp order. Each target is read by its own goroutine;
+// the heads of the per-target streams are merged as they arrive.
+func streamLogHistory(ctx context.Context, cli models.DockerAPI, targets []models.LogTarget, levels *models.LevelClassifier, fn func(models.LogEntry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	streams := make([]chan models.LogEntry, len(targets))
+	errs := make([]error, len(targets))
+	for i, target := range targets {
+		streams[i] = make(chan models.LogEntry, logStreamBuffer)
+		go func() {
+			defer close(streams[i])
+			reader, err := cli.ContainerLogs(ctx, target.ID, client.ContainerLogsOptions{
+				ShowStdout: true,
+				ShowStderr: true,
+				Timestamps: true,
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer reader.Close()
+
+			errs[i] = readLogStream(reader, target, levels, func(entry models.LogEntry) bool {
+				select {
+				case streams[i] <- entry:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+		}()
+	}
+
+	// heads holds the next unwritten line of each stream that is still open
+	heads := make([]*models.LogEntry, len(streams))
+	open := len(streams)
+	for i := range streams {
+		if entry, ok := <-streams[i]; ok {
+			heads[i] = &entry
+		} else {
+			open--
+		}
+	}
+
+	for open > 0 {
+		next := -1
+		for i, head := range heads {
+			if head != nil && (next < 0 || head.Time.Before(heads[next].Time)) {
+				next = i
+			}
+		}
+		if err := fn(*heads[next]); err != nil {
+			return err
+		}
+		if entry, ok := <-streams[next]; ok {
+			heads[next] = &entry
+		} else {
+			heads[next] = nil
+			open--
+		}
+	}
+
+	// Every stream is closed, so the reader goroutines have set errs
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
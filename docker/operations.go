@@ -1,16 +1,21 @@
 package docker
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"gdocker/config"
 	"gdocker/models"
 	"io"
 	"os/exec"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,272 +23,532 @@ import (
 	"github.com/moby/moby/client"
 )
 
-func init() {
-	// Set function pointers to avoid circular imports
-	models.GroupByProjectFunc = GroupByProject
-	models.RebuildItemsFunc = RebuildItems
-	models.RebuildVolumeItemsFunc = RebuildVolumeItems
-	models.RebuildImageItemsFunc = RebuildImageItems
-	models.RebuildNetworkItemsFunc = RebuildNetworkItems
-	models.RefreshContainersFunc = RefreshContainers
-	models.StartContainerFunc = StartContainer
-	models.StopContainerFunc = StopContainer
-	models.RestartContainerFunc = RestartContainer
-	models.DeleteContainerFunc = DeleteContainer
-	models.DeleteVolumeFunc = DeleteVolume
-	models.DeleteImageFunc = DeleteImage
-	models.LoadLogsFunc = LoadLogs
-	models.FollowLogsFunc = FollowLogs
-	models.LoadInspectFunc = LoadInspect
-	models.ExecShellFunc = ExecShell
-	models.OpenPortInBrowserFunc = OpenPortInBrowser
-	models.QuitFunc = Quit
-	models.LoadStatsFunc = LoadStats
-}
-
 func RefreshContainers(m *models.Model) tea.Cmd {
+	cli := m.DockerClient
+	env := m.EnvCache
+	timeout := m.TimeoutSettings().List
 	return func() tea.Msg {
-		containerList, err := m.DockerClient.ContainerList(context.Background(), client.ContainerListOptions{All: true})
+		ctx, cancel := withTimeout(context.Background(), timeout)
+		defer cancel()
+		containers, err := loadAllContainers(ctx, cli, env)
 		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Error: %v", err), Success: false}
+			return loadFailed(cli, models.ResourceContainers, ctxError(ctx, err, timeout))
 		}
+		return models.ContainersRefreshedMsg{Client: cli, Containers: containers}
+	}
+}
 
-		var containers []models.Container
-		for _, c := range containerList.Items {
-			name := strings.TrimPrefix(c.Names[0], "/")
-			project := c.Labels["com.docker.compose.project"]
+// action names a Docker action in status messages.
+type action struct {
+	verb    string // "stop"
+	running string // "Stopping"
+	done    string // "stopped"
+}
 
-			// Parse ports
-			var ports []models.PortMapping
-			for _, p := range c.Ports {
-				ip := ""
-				if p.IP.IsValid() {
-					ip = p.IP.String()
-				}
-				ports = append(ports, models.PortMapping{
-					PrivatePort: p.PrivatePort,
-					PublicPort:  p.PublicPort,
-					Type:        p.Type,
-					IP:          ip,
-				})
-			}
+var (
+	startAction   = action{"start", "Starting", "started"}
+	stopAction    = action{"stop", "Stopping", "stopped"}
+	restartAction = action{"restart", "Restarting", "restarted"}
+	deleteAction  = action{"delete", "Deleting", "deleted"}
+)
 
-			// Get environment variables by inspecting the container
-			var env []string
-			if inspect, err := m.DockerClient.ContainerInspect(context.Background(), c.ID, client.ContainerInspectOptions{}); err == nil {
-				env = inspect.Container.Config.Env
-			}
+// bulkParallelism caps how many items of a bulk action run at once.
+const bulkParallelism = 8
 
-			containers = append(containers, models.Container{
-				ID:      c.ID[:12],
-				Name:    name,
-				Image:   c.Image,
-				State:   string(c.State),
-				Status:  c.Status,
-				Project: project,
-				Created: time.Unix(c.Created, 0),
-				Ports:   ports,
-				Env:     env,
-			})
+// bulkTarget is one item of a bulk action.
+type bulkTarget struct {
+	name string // Shown in the summary
+	id   string // Passed to the Docker call
+}
+
+// bulkAction applies do to every target concurrently as one operation and
+// reports the outcome of each in a BulkResultMsg.
+func bulkAction(m *models.Model, a action, r models.Resource, targets []bulkTarget, do func(context.Context, models.DockerAPI, string) error) tea.Cmd {
+	cli := m.DockerClient
+	timeout := m.TimeoutSettings().Action
+	label := fmt.Sprintf("%s %d %s", a.running, len(targets), r)
+
+	return m.StartOperation(label, timeout, func(ctx context.Context) tea.Msg {
+		results := make([]models.ItemResult, len(targets))
+		sem := make(chan struct{}, bulkParallelism)
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			results[i].Name = target.name
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i].Err = ctxError(ctx, do(ctx, cli, target.id), timeout)
+			}()
 		}
+		wg.Wait()
+		return models.BulkResultMsg{Action: strings.ToUpper(a.done[:1]) + a.done[1:], Resource: r, Results: results}
+	})
+}
 
-		return models.ContainersRefreshedMsg{Containers: containers}
+// containerAction applies do to the containers the action targets: the
+// marked ones, or the container or compose project under the cursor.
+func containerAction(m *models.Model, a action, do func(context.Context, models.DockerAPI, string) error) tea.Cmd {
+	var containers []models.Container
+	for _, item := range m.ActionTargets() {
+		if item.IsContainer {
+			containers = append(containers, *item.Container)
+		}
+	}
+	if len(containers) == 0 {
+		return nil
 	}
+	if len(containers) > 1 {
+		targets := make([]bulkTarget, len(containers))
+		for i, c := range containers {
+			targets[i] = bulkTarget{name: c.Name, id: c.ID}
+		}
+		return bulkAction(m, a, models.ResourceContainers, targets, do)
+	}
+
+	cli := m.DockerClient
+	c := containers[0]
+	timeout := m.TimeoutSettings().Action
+
+	return m.StartOperation(a.running+" "+c.Name, timeout, func(ctx context.Context) tea.Msg {
+		if err := do(ctx, cli, c.ID); err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to %s: %v", a.verb, ctxError(ctx, err, timeout)), Success: false}
+		}
+		return models.ActionResultMsg{Message: "Container " + a.done, Success: true}
+	})
 }
 
 func StartContainer(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+	return containerAction(m, startAction, func(ctx context.Context, cli models.DockerAPI, id string) error {
+		_, err := cli.ContainerStart(ctx, id, client.ContainerStartOptions{})
+		return err
+	})
+}
+
+func StopContainer(m *models.Model) tea.Cmd {
+	return containerAction(m, stopAction, func(ctx context.Context, cli models.DockerAPI, id string) error {
+		stopTimeout := 10 // Seconds the container gets before it is killed
+		_, err := cli.ContainerStop(ctx, id, client.ContainerStopOptions{Timeout: &stopTimeout})
+		return err
+	})
+}
+
+func RestartContainer(m *models.Model) tea.Cmd {
+	return containerAction(m, restartAction, func(ctx context.Context, cli models.DockerAPI, id string) error {
+		stopTimeout := 10 // Seconds the container gets before it is killed
+		_, err := cli.ContainerRestart(ctx, id, client.ContainerRestartOptions{Timeout: &stopTimeout})
+		return err
+	})
+}
+
+func DeleteContainer(m *models.Model) tea.Cmd {
+	return containerAction(m, deleteAction, func(ctx context.Context, cli models.DockerAPI, id string) error {
+		_, err := cli.ContainerRemove(ctx, id, client.ContainerRemoveOptions{Force: true})
+		return err
+	})
+}
+
+func LoadLogs(m *models.Model) tea.Cmd {
+	if m.Cursor >= len(m.Items) {
 		return nil
 	}
 
-	containerID := m.Items[m.Cursor].Container.ID
+	// A container row shows that container; a project row merges all of its containers.
+	var containers []models.Container
+	project := ""
+	item := m.Items[m.Cursor]
+	switch {
+	case item.IsContainer:
+		containers = []models.Container{*item.Container}
+	case item.IsProject:
+		containers = append(containers, item.Project.Containers...)
+		project = item.Project.Name
+	default:
+		return nil
+	}
+	if len(containers) == 0 {
+		return nil
+	}
 
-	return func() tea.Msg {
-		_, err := m.DockerClient.ContainerStart(context.Background(), containerID, client.ContainerStartOptions{})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to start: %v", err), Success: false}
-		}
-		return models.ActionResultMsg{Message: "Container started", Success: true}
+	cli := m.DockerClient
+	levels := m.LevelClassifier
+	cfg := m.LogsSettings()
+	opts := client.ContainerLogsOptions{
+		Tail:  strconv.Itoa(cfg.TailLines),
+		Since: m.LogRangeSince,
+		Until: m.LogRangeUntil,
+	}
+	// A since bound asks for the whole range; the buffer cap still applies.
+	if opts.Since != "" {
+		opts.Tail = ""
+	}
+	t := m.TimeoutSettings()
+	label := "Loading logs of " + containers[0].Name
+	if project != "" {
+		label = "Loading logs of " + project
 	}
+
+	return m.StartOperation(label, t.Logs, func(ctx context.Context) tea.Msg {
+		targets := make([]models.LogTarget, len(containers))
+		batches := make([][]models.LogEntry, len(containers))
+		errs := make([]error, len(containers))
+
+		var wg sync.WaitGroup
+		for i, c := range containers {
+			targets[i] = models.LogTarget{ID: c.ID, Source: c.ServiceName()}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				targets[i].TTY = containerUsesTTY(ctx, cli, c.ID)
+				batches[i], errs[i] = fetchLogs(ctx, cli, targets[i], opts, levels)
+			}()
+		}
+		wg.Wait()
+
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				failed++
+			}
+		}
+		if failed == len(errs) {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to load logs: %v", ctxError(ctx, errs[0], t.Logs)), Success: false}
+		}
+
+		return models.LogsLoadedMsg{
+			Lines:   mergeLogEntries(batches),
+			Follow:  opts.Until == "", // Nothing new can arrive before a fixed end
+			Targets: targets,
+			Project: project,
+		}
+	})
 }
 
-func StopContainer(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+// maxOlderLogsRetries bounds how often LoadOlderLogs doubles its tail when
+// the lines written since the buffer was loaded push the page out of reach.
+const maxOlderLogsRetries = 6
+
+// LoadOlderLogs fetches the page of lines just before the oldest line in the
+// buffer. Docker has no offset for logs and applies tail before until, so
+// each target is re-read with a tail large enough to reach past what we
+// already hold, and only the strictly older lines are kept. Lines written
+// since the buffer was loaded count towards the tail too: while a fetch
+// comes back without a whole page, the tail is doubled and it is fetched
+// again. Until is left out so that a fetch returning less than its tail
+// shows the log starts there.
+func LoadOlderLogs(m *models.Model) tea.Cmd {
+	if len(m.LogTargets) == 0 {
 		return nil
 	}
 
-	containerID := m.Items[m.Cursor].Container.ID
+	oldest := make(map[string]time.Time)
+	held := make(map[string]int)
+	for _, entry := range m.Logs {
+		held[entry.Source]++
+		if t, ok := oldest[entry.Source]; !entry.Time.IsZero() && (!ok || entry.Time.Before(t)) {
+			oldest[entry.Source] = entry.Time
+		}
+	}
 
-	return func() tea.Msg {
-		timeout := 10
-		_, err := m.DockerClient.ContainerStop(context.Background(), containerID, client.ContainerStopOptions{Timeout: &timeout})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to stop: %v", err), Success: false}
+	cli := m.DockerClient
+	levels := m.LevelClassifier
+	targets := append([]models.LogTarget(nil), m.LogTargets...)
+	pageSize := m.LogsSettings().PageSize
+	since := m.LogRangeSince
+	timeout := m.TimeoutSettings().Logs
+	generation := m.LogGeneration
+
+	return m.StartOperation("Loading older logs", timeout, func(ctx context.Context) tea.Msg {
+		batches := make([][]models.LogEntry, len(targets))
+		atStart := make([]bool, len(targets))
+		errs := make([]error, len(targets))
+
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			before, ok := oldest[target.Source]
+			if !ok {
+				atStart[i] = true
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tail := held[target.Source] + pageSize
+				for range maxOlderLogsRetries + 1 {
+					lines, err := fetchLogs(ctx, cli, target, client.ContainerLogsOptions{
+						Tail:  strconv.Itoa(tail),
+						Since: since,
+					}, levels)
+					if err != nil {
+						errs[i] = err
+						return
+					}
+
+					var older []models.LogEntry
+					for _, entry := range lines {
+						if entry.Time.Before(before) {
+							older = append(older, entry)
+						}
+					}
+					if len(older) > pageSize {
+						older = older[len(older)-pageSize:]
+					}
+					batches[i] = older
+					atStart[i] = len(lines) < tail
+					if len(older) == pageSize || atStart[i] {
+						return
+					}
+					tail *= 2
+				}
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return models.OlderLogsLoadedMsg{Err: ctxError(ctx, err, timeout), Generation: generation}
+			}
+		}
+		return models.OlderLogsLoadedMsg{
+			Lines:      mergeLogEntries(batches),
+			AtStart:    !slices.Contains(atStart, false),
+			Generation: generation,
 		}
-		return models.ActionResultMsg{Message: "Container stopped", Success: true}
+	})
+}
+
+// withTimeout bounds ctx by d; zero leaves it without a deadline.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, d)
 }
 
-func RestartContainer(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
-		return nil
+var errCancelled = errors.New("cancelled")
+
+// ctxError replaces the error of a request whose context ended with why it
+// ended, which the client reports in varying, wordy ways.
+func ctxError(ctx context.Context, err error, timeout time.Duration) error {
+	switch {
+	case err == nil || ctx.Err() == nil:
+		return err
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return timeoutError{timeout}
+	default:
+		return errCancelled
 	}
+}
 
-	containerID := m.Items[m.Cursor].Container.ID
+// timeoutError is a request that ran out of time.
+type timeoutError struct {
+	after time.Duration
+}
 
-	return func() tea.Msg {
-		timeout := 10
-		_, err := m.DockerClient.ContainerRestart(context.Background(), containerID, client.ContainerRestartOptions{Timeout: &timeout})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to restart: %v", err), Success: false}
-		}
-		return models.ActionResultMsg{Message: "Container restarted", Success: true}
+func (e timeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s", e.after)
+}
+
+// containerUsesTTY reports whether the container was created with a TTY.
+// TTY containers write a single raw stream without multiplex headers.
+func containerUsesTTY(ctx context.Context, cli models.DockerAPI, containerID string) bool {
+	inspect, err := cli.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
+	if err != nil || inspect.Container.Config == nil {
+		return false
 	}
+	return inspect.Container.Config.Tty
 }
 
-func DeleteContainer(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
-		return nil
+// fetchLogs reads a target's log history within the tail/since/until bounds
+// of opts. Both streams and timestamps are always requested.
+func fetchLogs(ctx context.Context, cli models.DockerAPI, target models.LogTarget, opts client.ContainerLogsOptions, levels *models.LevelClassifier) ([]models.LogEntry, error) {
+	opts.ShowStdout = true
+	opts.ShowStderr = true
+	opts.Timestamps = true
+	opts.Follow = false
+
+	reader, err := cli.ContainerLogs(ctx, target.ID, opts)
+	if err != nil {
+		return nil, err
 	}
+	defer reader.Close()
 
-	containerID := m.Items[m.Cursor].Container.ID
+	var lines []models.LogEntry
+	err = readLogStream(reader, target, levels, func(entry models.LogEntry) bool {
+		lines = append(lines, entry)
+		return true
+	})
+	return lines, err
+}
 
-	return func() tea.Msg {
-		_, err := m.DockerClient.ContainerRemove(context.Background(), containerID, client.ContainerRemoveOptions{Force: true})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", err), Success: false}
-		}
-		return models.ActionResultMsg{Message: "Container deleted", Success: true}
+// mergeLogEntries interleaves per-container batches by their Docker timestamps.
+func mergeLogEntries(batches [][]models.LogEntry) []models.LogEntry {
+	if len(batches) == 1 {
+		return batches[0]
+	}
+
+	var merged []models.LogEntry
+	for _, batch := range batches {
+		merged = append(merged, batch...)
 	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged
 }
 
-func LoadLogs(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+// logStreamBuffer is how many lines a follow stream buffers ahead of the UI.
+const logStreamBuffer = 1024
+
+// FollowLogs opens a long-lived Follow stream for every log target and stores
+// it on the model. Lines are pushed from one goroutine per container until
+// the stream is cancelled or all containers stop producing output.
+func FollowLogs(m *models.Model) tea.Cmd {
+	if m.ViewMode != models.ViewLogs || !m.FollowingLogs || len(m.LogTargets) == 0 {
 		return nil
 	}
 
-	containerID := m.Items[m.Cursor].Container.ID
+	// Resume each container right after the newest line we already have.
+	newest := make(map[string]time.Time)
+	for _, entry := range m.Logs {
+		if entry.Time.After(newest[entry.Source]) {
+			newest[entry.Source] = entry.Time
+		}
+	}
 
-	return func() tea.Msg {
-		reader, err := m.DockerClient.ContainerLogs(context.Background(), containerID, client.ContainerLogsOptions{
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan models.LogEntry, logStreamBuffer)
+	done := make(chan error, 1)
+	stream := &models.LogStream{Lines: lines, Done: done, Cancel: cancel}
+	m.LogStream = stream
+
+	cli := m.DockerClient
+	levels := m.LevelClassifier
+	errs := make(chan error, len(m.LogTargets))
+	var wg sync.WaitGroup
+	for _, target := range m.LogTargets {
+		opts := client.ContainerLogsOptions{
 			ShowStdout: true,
 			ShowStderr: true,
-			Tail:       "100",
 			Timestamps: true,
-		})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to load logs: %v", err), Success: false}
+			Follow:     true,
+			Tail:       "0",
+		}
+		// Docker's "since" is inclusive, so step past the newest line by one nanosecond.
+		if ts, ok := newest[target.Source]; ok {
+			opts.Since = ts.Add(time.Nanosecond).Format(time.RFC3339Nano)
+			opts.Tail = ""
 		}
-		defer reader.Close()
-
-		var lines []string
-		buf := make([]byte, 8) // Docker log header is 8 bytes
-
-		for {
-			// Read header (8 bytes: stream type, padding, size)
-			_, err := io.ReadFull(reader, buf)
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				break
-			}
 
-			// Extract payload size from header (last 4 bytes, big-endian)
-			size := uint32(buf[4])<<24 | uint32(buf[5])<<16 | uint32(buf[6])<<8 | uint32(buf[7])
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			// Read the actual log line
-			payload := make([]byte, size)
-			_, err = io.ReadFull(reader, payload)
+			reader, err := cli.ContainerLogs(ctx, target.ID, opts)
 			if err != nil {
-				break
+				errs <- err
+				return
 			}
-
-			line := strings.TrimSpace(string(payload))
-			if line != "" {
-				lines = append(lines, line)
+			defer reader.Close()
+
+			err = readLogStream(reader, target, levels, func(entry models.LogEntry) bool {
+				select {
+				case lines <- entry:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				errs <- err
 			}
-		}
+		}()
+	}
 
-		since := time.Now()
-		if len(lines) > 0 {
-			if ts, ok := parseLogTimestamp(lines[len(lines)-1]); ok {
-				since = ts
-			}
+	go func() {
+		wg.Wait()
+		close(errs)
+		if ctx.Err() == nil {
+			done <- <-errs
 		}
+		close(done)
+		close(lines)
+	}()
 
-		return models.LogsLoadedMsg{
-			Lines:  lines,
-			Follow: true,
-			Since:  since,
-		}
-	}
+	return stream.Next()
 }
 
-func FollowLogs(m *models.Model) tea.Cmd {
-	if m.ViewMode != models.ViewLogs || !m.FollowingLogs {
-		return nil
+// readLogStream calls fn for each non-empty log line of target in reader,
+// classified by levels. TTY output is read as raw lines; everything else is
+// demultiplexed with readLogFrames.
+func readLogStream(reader io.Reader, target models.LogTarget, levels *models.LevelClassifier, fn func(models.LogEntry) bool) error {
+	emit := func(stream models.StreamType, line string) bool {
+		ts, _ := parseLogTimestamp(line)
+		entry := models.LogEntry{Stream: stream, Source: target.Source, Time: ts, Line: line}
+		entry.Level = levels.Classify(entry)
+		return fn(entry)
 	}
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
-		return nil
-	}
-
-	containerID := m.Items[m.Cursor].Container.ID
-	since := m.LogSince
-	if since.IsZero() {
-		since = time.Now()
+	if target.TTY {
+		return readRawLogLines(reader, emit)
 	}
+	return readLogFrames(reader, emit)
+}
 
-	return func() tea.Msg {
-		reader, err := m.DockerClient.ContainerLogs(context.Background(), containerID, client.ContainerLogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-			Tail:       "0",
-			Timestamps: true,
-			Since:      strconv.FormatInt(since.Unix(), 10),
-		})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to follow logs: %v", err), Success: false}
+// readRawLogLines reads unframed TTY output. Docker cannot tell stdout from
+// stderr here, so every line is attributed to stdout.
+func readRawLogLines(reader io.Reader, fn func(models.StreamType, string) bool) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !fn(models.StreamStdout, line) {
+			return nil
 		}
-		defer reader.Close()
-
-		lines, newest := decodeDockerLogStream(reader, time.Now())
-		return models.LogsFollowedMsg{Lines: lines, Since: newest}
 	}
+	return scanner.Err()
 }
 
-func decodeDockerLogStream(reader io.Reader, fallbackSince time.Time) ([]string, time.Time) {
-	var lines []string
+// readLogFrames decodes Docker's multiplexed log stream and calls fn for each
+// non-empty line. It stops when the reader is exhausted or fn returns false,
+// and returns any read error other than a clean EOF.
+func readLogFrames(reader io.Reader, fn func(models.StreamType, string) bool) error {
 	buf := make([]byte, 8) // Docker log header is 8 bytes
-	newest := fallbackSince
 
 	for {
+		// Read header (8 bytes: stream type, padding, size)
 		_, err := io.ReadFull(reader, buf)
 		if err == io.EOF {
-			break
+			return nil
 		}
 		if err != nil {
-			break
+			return err
 		}
 
+		// Extract payload size from header (last 4 bytes, big-endian)
 		size := uint32(buf[4])<<24 | uint32(buf[5])<<16 | uint32(buf[6])<<8 | uint32(buf[7])
 		payload := make([]byte, size)
-		_, err = io.ReadFull(reader, payload)
-		if err != nil {
-			break
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return err
+		}
+
+		// Byte 0 is the stream type: 1 = stdout, 2 = stderr.
+		stream := models.StreamStdout
+		if buf[0] == 2 {
+			stream = models.StreamStderr
 		}
 
 		line := strings.TrimSpace(string(payload))
 		if line == "" {
 			continue
 		}
-		lines = append(lines, line)
-		if ts, ok := parseLogTimestamp(line); ok && ts.After(newest) {
-			newest = ts
+		if !fn(stream, line) {
+			return nil
 		}
 	}
-
-	return lines, newest
 }
 
 func parseLogTimestamp(line string) (time.Time, bool) {
@@ -298,6 +563,9 @@ func parseLogTimestamp(line string) (time.Time, bool) {
 	return ts, true
 }
 
+// execShell starts bash where the image has it and sh otherwise.
+const execShell = "command -v bash >/dev/null && exec bash || exec sh"
+
 func ExecShell(m *models.Model) tea.Cmd {
 	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
 		return nil
@@ -306,24 +574,9 @@ func ExecShell(m *models.Model) tea.Cmd {
 	containerID := m.Items[m.Cursor].Container.ID
 	containerName := m.Items[m.Cursor].Container.Name
 
-	// Try different shells in order of preference
-	shells := []string{"/bin/bash", "/bin/sh", "/bin/ash"}
-
-	var selectedShell string
-	for _, shell := range shells {
-		// Test if shell exists
-		testCmd := exec.Command("docker", "exec", containerID, "test", "-f", shell)
-		if err := testCmd.Run(); err == nil {
-			selectedShell = shell
-			break
-		}
-	}
-
-	if selectedShell == "" {
-		selectedShell = "/bin/sh" // Fallback
-	}
-
-	cmd := exec.Command("docker", "exec", "-it", containerID, selectedShell)
+	// The container picks its shell itself, so nothing has to be probed
+	// from here while the UI waits.
+	cmd := dockerCLI(m, "exec", "-it", containerID, "sh", "-c", execShell)
 
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
 		if err != nil {
@@ -395,51 +648,90 @@ func OpenPortInBrowser(m *models.Model) tea.Cmd {
 	}
 }
 
-func InitialModel() (models.Model, error) {
+// Options are the command line settings that override the config file.
+type Options struct {
+	ReadOnly bool   // Disable operations that change the host
+	Context  string // Context or hosts profile to connect to
+}
+
+// InitialModel sets up the Docker client and a model that renders with
+// renderer. It does not wait for the daemon: the lists load concurrently
+// from Model.Init, so the UI starts even while Docker is down.
+func InitialModel(renderer models.Renderer, opts Options) (models.Model, error) {
 	// Load app config
 	appConfig, err := config.Load()
 	if err != nil {
 		// If config loading fails, use defaults.
 		appConfig = config.DefaultAppConfig()
 	}
-
-	// Connect to Docker. Configured host overrides DOCKER_HOST.
-	clientOpts := []client.Opt{
-		client.FromEnv,
-		client.WithAPIVersionNegotiation(),
+	if opts.ReadOnly {
+		appConfig.Docker.ReadOnly = true
+	}
+	if opts.Context != "" {
+		appConfig.Docker.Context = opts.Context
 	}
-	if appConfig.Docker.Host != "" {
-		clientOpts = append(clientOpts, client.WithHost(appConfig.Docker.Host))
+
+	// Connect to Docker. Without a context the configured host overrides
+	// DOCKER_HOST.
+	contexts := LoadContexts(appConfig)
+	active := contexts[0]
+	if name := appConfig.Docker.Context; name != "" {
+		i := slices.IndexFunc(contexts, func(c models.DockerContext) bool { return c.Name == name })
+		if i < 0 {
+			return models.Model{}, fmt.Errorf("unknown context %q", name)
+		}
+		active = contexts[i]
 	}
 
-	cli, err := client.NewClientWithOpts(clientOpts...)
+	cli, err := newClient(active)
 	if err != nil {
 		return models.Model{}, err
 	}
 
-	m := models.Model{
-		KeyBindings:     &appConfig.KeyBindings,
-		UIConfig:        &appConfig.UI,
-		DockerClient:    cli,
-		ViewMode:        models.ViewDetails,
-		NavMode:         models.NavContainers,
-		AutoRefreshSecs: appConfig.Docker.AutoRefreshSeconds,
+	// Bad user level patterns fall back to the built-in detection.
+	levels, levelErr := models.NewLevelClassifier(appConfig.Logs.LevelPatterns)
+
+	// In read-only mode the backend itself refuses to change the host
+	var services models.Services = Services{}
+	if appConfig.Docker.ReadOnly {
+		services = models.ReadOnlyServices{Services: services}
 	}
 
-	// Load initial data
-	if err := LoadContainers(&m); err != nil {
-		return models.Model{}, err
+	m := models.NewModel(cli, services, renderer)
+	m.ReadOnly = appConfig.Docker.ReadOnly
+	m.Contexts = contexts
+	m.Context = active.Name
+	m.KeyBindings = &appConfig.KeyBindings
+	m.UIConfig = &appConfig.UI
+	m.LogsConfig = &appConfig.Logs
+	m.TopConfig = &appConfig.Top
+	m.AlertMonitor = &appConfig.AlertMonitor
+	m.AutoRefreshSecs = appConfig.Docker.AutoRefreshSeconds
+	m.Timeouts = &appConfig.Docker.Timeouts
+	m.TopSort = models.TopColumnCPU
+	m.LevelClassifier = levels
+	m.SearchSmartCase = appConfig.Logs.SmartCase
+	m.SearchContext = appConfig.Logs.SearchContext
+	m.AlertRules = appConfig.Alerts
+	m.Safety = &appConfig.Safety
+	if levelErr != nil {
+		m.StatusMessage = "Config: " + levelErr.Error()
 	}
-	if err := LoadVolumes(&m); err != nil {
-		return models.Model{}, err
+	// A bad rule disables the monitor rather than matching the wrong containers.
+	if err := models.ValidateAlertRules(&m); err != nil {
+		m.AlertRules = nil
+		m.StatusMessage = "Config: " + err.Error()
 	}
-	if err := LoadImages(&m); err != nil {
-		return models.Model{}, err
-	}
-	if err := LoadNetworks(&m); err != nil {
-		return models.Model{}, err
+	// A bad protected pattern still protects its exact name
+	if err := models.ValidateProtected(&m); err != nil {
+		m.StatusMessage = "Config: " + err.Error()
 	}
 
+	// Events keep the lists current once they are loaded. Subscribing
+	// first means no change made while they load is missed.
+	m.StartLoading()
+	m.EventStream = openEventStream(cli, time.Time{})
+
 	return m, nil
 }
 
@@ -453,97 +745,226 @@ func Quit(m *models.Model) {
 	}
 }
 
+// LoadStats opens a streaming ContainerStats subscription for the selected
+// container and stores it on the model. Docker sends a sample about once a
+// second until the stream is cancelled or the container goes away.
 func LoadStats(m *models.Model) tea.Cmd {
 	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
 		return nil
 	}
 
 	containerID := m.Items[m.Cursor].Container.ID
-
-	return func() tea.Msg {
-		ctx := context.Background()
-
-		// Take a first snapshot.
-		v, err := getContainerStatsSnapshot(ctx, m.DockerClient, containerID)
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to load stats: %v", err), Success: false}
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := make(chan models.ContainerStats, 1)
+	done := make(chan error, 1)
+	stream := &models.StatsStream{Samples: samples, Done: done, Cancel: cancel}
+	m.StatsStream = stream
+
+	cli := m.DockerClient
+	go func() {
+		err := streamContainerStats(ctx, cli, containerID, func(sample models.ContainerStats) bool {
+			select {
+			case samples <- sample:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if ctx.Err() == nil {
+			done <- err
 		}
+		close(done)
+		close(samples)
+	}()
 
-		// Calculate CPU from Docker's pre-cpu delta first.
-		cpuPercent := calculateCPUPercent(v.CPUStats, v.PreCPUStats)
+	return stream.Next()
+}
+
+// streamContainerStats decodes a streaming stats response and calls fn with
+// each sample, with rates computed against the previous one, until fn
+// returns false or the stream ends.
+func streamContainerStats(ctx context.Context, cli models.DockerAPI, containerID string, fn func(models.ContainerStats) bool) error {
+	resp, err := cli.ContainerStats(ctx, containerID, client.ContainerStatsOptions{Stream: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-		// Some daemons/cgroup setups return empty/zero pre-cpu on first read.
-		// Fallback: take a second snapshot and compute deltas between snapshots.
-		if cpuPercent == 0.0 {
-			time.Sleep(250 * time.Millisecond)
-			v2, err := getContainerStatsSnapshot(ctx, m.DockerClient, containerID)
-			if err == nil {
-				cpuPercent = calculateCPUPercent(v2.CPUStats, v.CPUStats)
-				v = v2 // Keep the freshest values for memory/net/block/pids.
+	decoder := json.NewDecoder(resp.Body)
+	var prev *models.ContainerStats
+	for {
+		var v container.StatsResponse
+		if err := decoder.Decode(&v); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
 			}
+			return err
 		}
 
-		// Calculate memory usage
-		memUsage := v.MemoryStats.Usage
-		memLimit := v.MemoryStats.Limit
-		memPercent := 0.0
-		if memLimit > 0 {
-			memPercent = (float64(memUsage) / float64(memLimit)) * 100.0
+		sample := decodeStats(containerID, v, prev)
+		if !fn(sample) {
+			return nil
 		}
+		prev = &sample
+	}
+}
+
+// decodeStats converts a Docker stats response into a sample. CPU usage comes
+// from Docker's own pre-cpu delta, or from the previous sample when Docker
+// sent none (one-shot requests); byte rates always need the previous sample.
+func decodeStats(containerID string, v container.StatsResponse, prev *models.ContainerStats) models.ContainerStats {
+	sample := models.ContainerStats{
+		ContainerID:    containerID,
+		Time:           v.Read,
+		CPUPercent:     calculateCPUPercent(v.CPUStats, v.PreCPUStats),
+		MemUsage:       v.MemoryStats.Usage,
+		MemLimit:       v.MemoryStats.Limit,
+		PIDs:           v.PidsStats.Current,
+		CPUTotalUsage:  v.CPUStats.CPUUsage.TotalUsage,
+		SystemCPUUsage: v.CPUStats.SystemUsage,
+	}
+	if v.PreCPUStats.SystemUsage == 0 && prev != nil {
+		previous := container.CPUStats{SystemUsage: prev.SystemCPUUsage}
+		previous.CPUUsage.TotalUsage = prev.CPUTotalUsage
+		sample.CPUPercent = calculateCPUPercent(v.CPUStats, previous)
+	}
+	if sample.MemLimit > 0 {
+		sample.MemPercent = float64(sample.MemUsage) / float64(sample.MemLimit) * 100.0
+	}
 
-		// Format memory usage
-		memUsageStr := formatBytes(memUsage)
-		memLimitStr := formatBytes(memLimit)
+	for _, network := range v.Networks {
+		sample.NetRx += network.RxBytes
+		sample.NetTx += network.TxBytes
+	}
+	for _, bio := range v.BlkioStats.IoServiceBytesRecursive {
+		switch bio.Op {
+		case "read", "Read":
+			sample.BlockRead += bio.Value
+		case "write", "Write":
+			sample.BlockWrite += bio.Value
+		}
+	}
 
-		// Calculate network I/O
-		var rxBytes, txBytes uint64
-		for _, network := range v.Networks {
-			rxBytes += network.RxBytes
-			txBytes += network.TxBytes
+	if prev != nil {
+		if elapsed := sample.Time.Sub(prev.Time).Seconds(); elapsed > 0 {
+			sample.NetRxRate = counterRate(prev.NetRx, sample.NetRx, elapsed)
+			sample.NetTxRate = counterRate(prev.NetTx, sample.NetTx, elapsed)
+			sample.BlockReadRate = counterRate(prev.BlockRead, sample.BlockRead, elapsed)
+			sample.BlockWriteRate = counterRate(prev.BlockWrite, sample.BlockWrite, elapsed)
 		}
-		netIO := fmt.Sprintf("%s / %s", formatBytes(rxBytes), formatBytes(txBytes))
+	}
+	return sample
+}
 
-		// Calculate block I/O
-		var readBytes, writeBytes uint64
-		for _, bio := range v.BlkioStats.IoServiceBytesRecursive {
-			switch bio.Op {
-			case "read", "Read":
-				readBytes += bio.Value
-			case "write", "Write":
-				writeBytes += bio.Value
-			}
+// LoadTopStats takes one stats sample of every running container for the
+// overview. Rates and CPU usage are computed against the previous round.
+func LoadTopStats(m *models.Model) tea.Cmd {
+	var ids []string
+	for _, c := range m.Containers {
+		if c.State == "running" {
+			ids = append(ids, c.ID)
 		}
-		blockIO := fmt.Sprintf("%s / %s", formatBytes(readBytes), formatBytes(writeBytes))
+	}
 
-		// Get PIDs
-		pids := fmt.Sprintf("%d", v.PidsStats.Current)
+	cli := m.DockerClient
+	prev := m.TopStats
+	generation := m.TopGeneration
+	parallelism := m.TopSettings().Parallelism
+	timeout := m.TimeoutSettings().Stats
 
-		return models.StatsLoadedMsg{
-			Stats: &models.ContainerStats{
-				CPUPerc:     fmt.Sprintf("%.2f%%", cpuPercent),
-				MemUsage:    fmt.Sprintf("%s / %s", memUsageStr, memLimitStr),
-				MemPerc:     fmt.Sprintf("%.2f%%", memPercent),
-				NetIO:       netIO,
-				BlockIO:     blockIO,
-				PIDs:        pids,
-				ContainerID: containerID,
-			},
+	return func() tea.Msg {
+		stats, failed := sampleContainerStats(cli, ids, prev, parallelism, timeout)
+		return models.TopStatsLoadedMsg{Generation: generation, Stats: stats, Failed: failed}
+	}
+}
+
+// LoadProjectStats samples the running containers of the selected compose
+// project for its resource panel.
+func LoadProjectStats(m *models.Model) tea.Cmd {
+	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsProject {
+		return nil
+	}
+
+	project := m.Items[m.Cursor].Project
+	var prev map[string]models.ContainerStats
+	if m.ProjectStatsName == project.Name {
+		prev = m.ProjectStats
+	}
+	var ids []string
+	for _, c := range project.Containers {
+		if c.State == "running" {
+			ids = append(ids, c.ID)
 		}
 	}
+
+	cli := m.DockerClient
+	parallelism := m.TopSettings().Parallelism
+	timeout := m.TimeoutSettings().Stats
+
+	return func() tea.Msg {
+		stats, failed := sampleContainerStats(cli, ids, prev, parallelism, timeout)
+		return models.ProjectStatsLoadedMsg{Project: project.Name, Stats: stats, Failed: failed}
+	}
 }
 
-func getContainerStatsSnapshot(ctx context.Context, cli *client.Client, containerID string) (container.StatsResponse, error) {
-	stats, err := cli.ContainerStats(ctx, containerID, client.ContainerStatsOptions{Stream: false})
-	if err != nil {
-		return container.StatsResponse{}, err
+// sampleContainerStats takes one stats sample of each container, querying at
+// most parallelism containers at a time. Containers that could not be read
+// are counted in failed; each sample is bounded by timeout.
+func sampleContainerStats(cli models.DockerAPI, ids []string, prevStats map[string]models.ContainerStats, parallelism int, timeout time.Duration) (map[string]models.ContainerStats, int) {
+	results := make([]*models.ContainerStats, len(ids))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ctx, cancel := withTimeout(context.Background(), timeout)
+			defer cancel()
+
+			prev, ok := prevStats[id]
+			// Without an earlier sample, let Docker take two so the CPU
+			// delta is available in the first round.
+			resp, err := cli.ContainerStats(ctx, id, client.ContainerStatsOptions{IncludePreviousSample: !ok})
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+
+			var v container.StatsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+				return
+			}
+			var prevSample *models.ContainerStats
+			if ok {
+				prevSample = &prev
+			}
+			sample := decodeStats(id, v, prevSample)
+			results[i] = &sample
+		}()
 	}
-	defer stats.Body.Close()
+	wg.Wait()
 
-	var v container.StatsResponse
-	if err := json.NewDecoder(stats.Body).Decode(&v); err != nil {
-		return container.StatsResponse{}, err
+	stats := make(map[string]models.ContainerStats, len(ids))
+	failed := 0
+	for _, sample := range results {
+		if sample == nil {
+			failed++
+			continue
+		}
+		stats[sample.ContainerID] = *sample
+	}
+	return stats, failed
+}
+
+// counterRate is the per-second increase of a cumulative counter. A counter
+// that went backwards was reset, e.g. by a restart, and reports zero.
+func counterRate(prev, cur uint64, seconds float64) float64 {
+	if cur < prev {
+		return 0
 	}
-	return v, nil
+	return float64(cur-prev) / seconds
 }
 
 func calculateCPUPercent(current, previous container.CPUStats) float64 {
@@ -564,106 +985,136 @@ func calculateCPUPercent(current, previous container.CPUStats) float64 {
 	return (cpuDelta / systemDelta) * cpuCount * 100.0
 }
 
-func formatBytes(bytes uint64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
+func DeleteVolume(m *models.Model) tea.Cmd {
+	var targets []bulkTarget
+	for _, item := range m.ActionTargets() {
+		if item.IsVolume {
+			targets = append(targets, bulkTarget{name: item.Volume.Name, id: item.Volume.Name})
+		}
 	}
-	div, exp := uint64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+	remove := func(ctx context.Context, cli models.DockerAPI, name string) error {
+		_, err := cli.VolumeRemove(ctx, name, client.VolumeRemoveOptions{Force: true})
+		return err
 	}
-	return fmt.Sprintf("%.2f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
-
-func DeleteVolume(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsVolume {
+	if len(targets) == 0 {
 		return nil
 	}
+	if len(targets) > 1 {
+		return bulkAction(m, deleteAction, models.ResourceVolumes, targets, remove)
+	}
 
-	volumeName := m.Items[m.Cursor].Volume.Name
+	cli := m.DockerClient
+	volumeName := targets[0].id
+	timeout := m.TimeoutSettings().Action
 
-	return func() tea.Msg {
-		_, err := m.DockerClient.VolumeRemove(context.Background(), volumeName, client.VolumeRemoveOptions{Force: true})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", err), Success: false}
+	return m.StartOperation("Deleting volume "+volumeName, timeout, func(ctx context.Context) tea.Msg {
+		if err := remove(ctx, cli, volumeName); err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
 
 		// Reload volumes
-		volumeList, err := m.DockerClient.VolumeList(context.Background(), client.VolumeListOptions{})
+		volumes, err := listVolumes(ctx, cli)
 		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload volumes: %v", err), Success: false}
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload volumes: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
-
-		var volumes []models.Volume
-		for _, v := range volumeList.Items {
-			// Parse the CreatedAt timestamp
-			created := time.Now()
-			if v.CreatedAt != "" {
-				if t, err := time.Parse(time.RFC3339Nano, v.CreatedAt); err == nil {
-					created = t
-				}
-			}
-
-			volumes = append(volumes, models.Volume{
-				Name:       v.Name,
-				Driver:     v.Driver,
-				Mountpoint: v.Mountpoint,
-				Created:    created,
-				Labels:     v.Labels,
-				Scope:      v.Scope,
-			})
-		}
-
-		return models.VolumesLoadedMsg{Volumes: volumes}
-	}
+		return models.VolumesLoadedMsg{Client: cli, Volumes: volumes, Message: "Volume deleted"}
+	})
 }
 
 func DeleteImage(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsImage {
+	var targets []bulkTarget
+	for _, item := range m.ActionTargets() {
+		if item.IsImage {
+			targets = append(targets, bulkTarget{name: imageLabel(item.Image), id: item.Image.ID})
+		}
+	}
+	remove := func(ctx context.Context, cli models.DockerAPI, id string) error {
+		_, err := cli.ImageRemove(ctx, id, client.ImageRemoveOptions{Force: true})
+		return err
+	}
+	if len(targets) == 0 {
 		return nil
 	}
+	if len(targets) > 1 {
+		return bulkAction(m, deleteAction, models.ResourceImages, targets, remove)
+	}
 
-	imageID := m.Items[m.Cursor].Image.ID
+	cli := m.DockerClient
+	target := targets[0]
+	timeout := m.TimeoutSettings().Action
 
-	return func() tea.Msg {
-		_, err := m.DockerClient.ImageRemove(context.Background(), imageID, client.ImageRemoveOptions{Force: true})
-		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", err), Success: false}
+	return m.StartOperation("Deleting image "+target.name, timeout, func(ctx context.Context) tea.Msg {
+		if err := remove(ctx, cli, target.id); err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
 
 		// Reload images
-		imageList, err := m.DockerClient.ImageList(context.Background(), client.ImageListOptions{All: true})
+		images, err := listImages(ctx, cli)
 		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload images: %v", err), Success: false}
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload images: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
+		return models.ImagesLoadedMsg{Client: cli, Images: images, Message: "Image deleted"}
+	})
+}
 
-		var images []models.Image
-		for _, img := range imageList.Items {
-			images = append(images, models.Image{
-				ID:       img.ID[7:19], // Short ID (remove "sha256:" prefix and truncate)
-				RepoTags: img.RepoTags,
-				Size:     img.Size,
-				Created:  time.Unix(img.Created, 0),
-			})
+func DeleteNetwork(m *models.Model) tea.Cmd {
+	var targets []bulkTarget
+	for _, item := range m.ActionTargets() {
+		if item.IsNetwork {
+			targets = append(targets, bulkTarget{name: item.Network.Name, id: item.Network.ID})
+		}
+	}
+	remove := func(ctx context.Context, cli models.DockerAPI, id string) error {
+		_, err := cli.NetworkRemove(ctx, id, client.NetworkRemoveOptions{})
+		return err
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	if len(targets) > 1 {
+		return bulkAction(m, deleteAction, models.ResourceNetworks, targets, remove)
+	}
+
+	cli := m.DockerClient
+	target := targets[0]
+	timeout := m.TimeoutSettings().Action
+
+	return m.StartOperation("Deleting network "+target.name, timeout, func(ctx context.Context) tea.Msg {
+		if err := remove(ctx, cli, target.id); err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to delete: %v", ctxError(ctx, err, timeout)), Success: false}
+		}
+
+		// Reload networks
+		networks, err := listNetworks(ctx, cli)
+		if err != nil {
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to reload networks: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
+		return models.NetworksLoadedMsg{Client: cli, Networks: networks, Message: "Network deleted"}
+	})
+}
 
-		return models.ImagesLoadedMsg{Images: images}
+// imageLabel names an image by its first tag, or its short ID.
+func imageLabel(img *models.Image) string {
+	if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+		return img.RepoTags[0]
 	}
+	return img.ID
 }
 
 func LoadInspect(m *models.Model) tea.Cmd {
-	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+	c := m.SelectedContainer()
+	if c == nil {
 		return nil
 	}
 
-	containerID := m.Items[m.Cursor].Container.ID
+	cli := m.DockerClient
+	containerID := c.ID
+	timeout := m.TimeoutSettings().Inspect
 
-	return func() tea.Msg {
-		data, err := m.DockerClient.ContainerInspect(context.Background(), containerID, client.ContainerInspectOptions{})
+	return m.StartOperation("Inspecting "+c.Name, timeout, func(ctx context.Context) tea.Msg {
+		data, err := cli.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
 		if err != nil {
-			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to inspect: %v", err), Success: false}
+			return models.ActionResultMsg{Message: fmt.Sprintf("Failed to inspect: %v", ctxError(ctx, err, timeout)), Success: false}
 		}
 
 		// Convert to pretty JSON
@@ -673,5 +1124,5 @@ func LoadInspect(m *models.Model) tea.Cmd {
 		}
 
 		return models.InspectLoadedMsg{Data: string(jsonData)}
-	}
+	})
 }
@@ -0,0 +1,408 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"gdocker/config"
+	"gdocker/docker/dockertest"
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/api/types/container"
+)
+
+// frame encodes one multiplexed log frame as the daemon sends it.
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+type logLine struct {
+	stream models.StreamType
+	line   string
+}
+
+func TestReadLogFrames(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frame(1, "hello\n"))
+	buf.Write(frame(2, "oops\n"))
+	buf.Write(frame(1, "  \n")) // blank lines are skipped
+	buf.Write(frame(1, "bye\n"))
+
+	var got []logLine
+	err := readLogFrames(&buf, func(stream models.StreamType, line string) bool {
+		got = append(got, logLine{stream, line})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("readLogFrames: %v", err)
+	}
+
+	want := []logLine{
+		{models.StreamStdout, "hello"},
+		{models.StreamStderr, "oops"},
+		{models.StreamStdout, "bye"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadLogFramesStopsEarly(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frame(1, "one\n"))
+	buf.Write(frame(1, "two\n"))
+
+	n := 0
+	err := readLogFrames(&buf, func(models.StreamType, string) bool {
+		n++
+		return false
+	})
+	if err != nil || n != 1 {
+		t.Errorf("read %d lines with err %v, want 1 and nil", n, err)
+	}
+}
+
+func TestReadLogFramesTruncated(t *testing.T) {
+	data := frame(1, "complete line\n")
+	err := readLogFrames(bytes.NewReader(data[:len(data)-3]), func(models.StreamType, string) bool { return true })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestReadRawLogLines(t *testing.T) {
+	var got []logLine
+	err := readRawLogLines(bytes.NewBufferString("first\r\n\nsecond\n"), func(stream models.StreamType, line string) bool {
+		got = append(got, logLine{stream, line})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("readRawLogLines: %v", err)
+	}
+	if len(got) != 2 || got[0] != (logLine{models.StreamStdout, "first"}) || got[1].line != "second" {
+		t.Errorf("got %+v, want first and second on stdout", got)
+	}
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	ts, ok := parseLogTimestamp("2024-05-01T10:00:00.123456789Z hello world")
+	if !ok || !ts.Equal(time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC)) {
+		t.Errorf("parseLogTimestamp = %v, %v", ts, ok)
+	}
+	if _, ok := parseLogTimestamp("no timestamp here"); ok {
+		t.Error("parsed a timestamp from a plain line")
+	}
+}
+
+// result runs cmd and returns the message of the operation it started.
+func result(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		msg = batch[0]() // The operation; the spinner tick follows
+	}
+	if done, ok := msg.(models.OperationDoneMsg); ok {
+		return done.Msg
+	}
+	return msg
+}
+
+func TestLoadLogsContainer(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: []dockertest.LogLine{
+		{Time: base, Text: "starting"},
+		{Time: base.Add(time.Second), Stderr: true, Text: "ERROR failed to bind"},
+		{Time: base.Add(2 * time.Second), Text: "listening"},
+	}})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+
+	msg, ok := result(LoadLogs(m)).(models.LogsLoadedMsg)
+	if !ok {
+		t.Fatalf("LoadLogs did not return LogsLoadedMsg")
+	}
+	if len(msg.Lines) != 3 || !msg.Follow || msg.Project != "" {
+		t.Fatalf("got %d lines follow=%v project=%q", len(msg.Lines), msg.Follow, msg.Project)
+	}
+	errLine := msg.Lines[1]
+	if errLine.Stream != models.StreamStderr || errLine.Level != models.LevelError || !errLine.Time.Equal(base.Add(time.Second)) {
+		t.Errorf("second line = %+v, want a timestamped stderr error", errLine)
+	}
+	if got := errLine.Message(); got != "ERROR failed to bind" {
+		t.Errorf("Message() = %q", got)
+	}
+}
+
+func TestLoadLogsProjectMergesByTime(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "shop-api-1", Project: "shop", Service: "api", Logs: []dockertest.LogLine{
+		{Time: base, Text: "api 1"},
+		{Time: base.Add(2 * time.Second), Text: "api 2"},
+	}})
+	// A TTY container writes raw, unframed output
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "shop-db-1", Project: "shop", Service: "db", TTY: true, Logs: []dockertest.LogLine{
+		{Time: base.Add(time.Second), Text: "db 1"},
+	}})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+	m.Cursor = 0 // the project row
+
+	msg, ok := result(LoadLogs(m)).(models.LogsLoadedMsg)
+	if !ok {
+		t.Fatalf("LoadLogs did not return LogsLoadedMsg")
+	}
+	if msg.Project != "shop" || len(msg.Targets) != 2 {
+		t.Fatalf("project = %q with %d targets", msg.Project, len(msg.Targets))
+	}
+	var got []string
+	for _, l := range msg.Lines {
+		got = append(got, l.Source+": "+l.Message())
+	}
+	want := []string{"api-1: api 1", "db-1: db 1", "api-1: api 2"}
+	if len(got) != len(want) {
+		t.Fatalf("lines = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadLogsTail(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var lines []dockertest.LogLine
+	for i := range 10 {
+		lines = append(lines, dockertest.LogLine{Time: base.Add(time.Duration(i) * time.Second), Text: "line"})
+	}
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: lines})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+	cfg := m.LogsSettings()
+	cfg.TailLines = 4
+	m.LogsConfig = &cfg
+
+	msg := result(LoadLogs(m)).(models.LogsLoadedMsg)
+	if len(msg.Lines) != 4 || !msg.Lines[0].Time.Equal(base.Add(6*time.Second)) {
+		t.Errorf("got %d lines starting %v, want the last 4", len(msg.Lines), msg.Lines[0].Time)
+	}
+}
+
+func TestLoadOlderLogsReachesPastNewLines(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	line := func(i int) dockertest.LogLine {
+		return dockertest.LogLine{Time: base.Add(time.Duration(i) * time.Second), Text: fmt.Sprintf("line %d", i)}
+	}
+	var lines []dockertest.LogLine
+	for i := range 10 {
+		lines = append(lines, line(i))
+	}
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: lines})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+	cfg := m.LogsSettings()
+	cfg.TailLines, cfg.PageSize = 3, 2
+	m.LogsConfig = &cfg
+
+	loaded := result(LoadLogs(m)).(models.LogsLoadedMsg)
+	m.Logs, m.LogTargets = loaded.Lines, loaded.Targets
+	// More than a page is written after the buffer was loaded
+	for i := 10; i < 15; i++ {
+		fake.AppendLogs("aaaaaaaaaaaa", line(i))
+	}
+
+	msg := result(LoadOlderLogs(m)).(models.OlderLogsLoadedMsg)
+	if msg.Err != nil || len(msg.Lines) != 2 || msg.Lines[0].Message() != "line 5" || msg.Lines[1].Message() != "line 6" {
+		t.Fatalf("older = %+v, err %v; want lines 5 and 6", msg.Lines, msg.Err)
+	}
+	if msg.AtStart {
+		t.Error("AtStart set with 5 lines of history left")
+	}
+
+	m.Logs = append(msg.Lines, m.Logs...)
+	for range 4 {
+		msg = result(LoadOlderLogs(m)).(models.OlderLogsLoadedMsg)
+		m.Logs = append(msg.Lines, m.Logs...)
+	}
+	if len(msg.Lines) != 0 || !msg.AtStart || len(m.Logs) != 10 {
+		t.Errorf("after reading back: %d lines held, last page %d, AtStart %v", len(m.Logs), len(msg.Lines), msg.AtStart)
+	}
+}
+
+func TestLoadLogsFailure(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web"})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+	fake.Fail("ContainerLogs", errors.New("boom"))
+
+	msg, ok := result(LoadLogs(m)).(models.ActionResultMsg)
+	if !ok || msg.Success {
+		t.Errorf("LoadLogs = %+v, want a failed ActionResultMsg", msg)
+	}
+}
+
+func TestLoadAlertStatsUsesRulesAtCall(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "api-1", Stats: []container.StatsResponse{{}}})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "web", Stats: []container.StatsResponse{{}}})
+	m := newTestModel(t, fake)
+	m.AlertRules = []config.AlertRule{{Container: "api*", CPUPercent: 90}}
+
+	cmd := LoadAlertStats(m)
+	// The rules change before the round runs
+	m.AlertRules = []config.AlertRule{{Container: "*", CPUPercent: 90}}
+
+	msg := cmd().(models.AlertStatsLoadedMsg)
+	if msg.Err != nil || len(msg.Names) != 1 || msg.Names["aaaaaaaaaaaa"] != "api-1" {
+		t.Errorf("names = %v, err %v; want only api-1", msg.Names, msg.Err)
+	}
+}
+
+func TestSampleContainerStats(t *testing.T) {
+	sample := container.StatsResponse{}
+	sample.CPUStats.CPUUsage.TotalUsage = 400
+	sample.CPUStats.SystemUsage = 2000
+	sample.CPUStats.OnlineCPUs = 2
+	sample.PreCPUStats.CPUUsage.TotalUsage = 200
+	sample.PreCPUStats.SystemUsage = 1000
+	sample.MemoryStats.Usage = 256
+	sample.MemoryStats.Limit = 1024
+
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Stats: []container.StatsResponse{sample}})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "no-stats"})
+
+	stats, failed := sampleContainerStats(fake, []string{"aaaaaaaaaaaa", "bbbbbbbbbbbb"}, nil, 2, time.Second)
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+	got, ok := stats["aaaaaaaaaaaa"]
+	if !ok {
+		t.Fatal("no sample for web")
+	}
+	// 200 of 1000 system ticks on 2 CPUs
+	if got.CPUPercent != 40 || got.MemPercent != 25 {
+		t.Errorf("CPU %.1f%% MEM %.1f%%, want 40%% and 25%%", got.CPUPercent, got.MemPercent)
+	}
+}
+
+func TestContainerActions(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", State: "exited"})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+
+	if msg := result(StartContainer(m)).(models.ActionResultMsg); !msg.Success {
+		t.Errorf("start: %s", msg.Message)
+	}
+	if msg := result(RestartContainer(m)).(models.ActionResultMsg); !msg.Success {
+		t.Errorf("restart: %s", msg.Message)
+	}
+	if msg := result(StopContainer(m)).(models.ActionResultMsg); !msg.Success {
+		t.Errorf("stop: %s", msg.Message)
+	}
+	if msg := result(DeleteContainer(m)).(models.ActionResultMsg); !msg.Success {
+		t.Errorf("delete: %s", msg.Message)
+	}
+	for _, method := range []string{"ContainerStart", "ContainerRestart", "ContainerStop", "ContainerRemove"} {
+		if calls := fake.Calls(method); len(calls) != 1 || calls[0].ID != "aaaaaaaaaaaa" {
+			t.Errorf("%s calls = %+v", method, calls)
+		}
+	}
+
+	// The container is gone now
+	if msg := result(StartContainer(m)).(models.ActionResultMsg); msg.Success {
+		t.Error("starting a removed container succeeded")
+	}
+}
+
+func TestRequestsTimeOut(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web"})
+	m := newTestModel(t, fake)
+	load(t, m, RefreshContainers)
+	m.Timeouts = &config.TimeoutConfig{List: 20 * time.Millisecond, Action: 20 * time.Millisecond}
+
+	fake.Hang("ContainerStop", true)
+	if msg := result(StopContainer(m)).(models.ActionResultMsg); msg.Success || msg.Message != "Failed to stop: timed out after 20ms" {
+		t.Errorf("stop = %+v, want a timeout", msg)
+	}
+
+	// A daemon that stops answering is treated like an unreachable one
+	fake.Hang("ContainerList", true)
+	msg, ok := RefreshContainers(m)().(models.ResourceFailedMsg)
+	if !ok || !msg.Unreachable || msg.Err.Error() != "timed out after 20ms" {
+		t.Errorf("refresh = %+v, want an unreachable timeout", msg)
+	}
+}
+
+func TestDeleteVolumeReloads(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddVolume("data", "local")
+	fake.AddVolume("cache", "local")
+	m := newTestModel(t, fake)
+	load(t, m, ReloadVolumes)
+	RebuildVolumeItems(m)
+
+	msg, ok := result(DeleteVolume(m)).(models.VolumesLoadedMsg)
+	if !ok || len(msg.Volumes) != 1 || msg.Volumes[0].Name != "cache" || msg.Message != "Volume deleted" {
+		t.Errorf("DeleteVolume = %+v, want only cache left", msg)
+	}
+}
+
+func TestDeleteMarkedVolumes(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddVolume("data", "local")
+	fake.AddVolume("cache", "local")
+	fake.AddVolume("logs", "local")
+	m := newTestModel(t, fake)
+	load(t, m, ReloadVolumes)
+	m.NavMode = models.NavVolumes
+	RebuildVolumeItems(m)
+	m.Marked = map[string]bool{"volume:data": true, "volume:logs": true}
+
+	msg, ok := result(DeleteVolume(m)).(models.BulkResultMsg)
+	if !ok || msg.Action != "Deleted" || msg.Resource != models.ResourceVolumes || len(msg.Results) != 2 {
+		t.Fatalf("DeleteVolume = %+v, want a bulk result for both marked volumes", msg)
+	}
+	for _, r := range msg.Results {
+		if r.Err != nil {
+			t.Errorf("deleting %s: %v", r.Name, r.Err)
+		}
+	}
+	if calls := fake.Calls("VolumeRemove"); len(calls) != 2 {
+		t.Errorf("VolumeRemove calls = %+v, want 2", calls)
+	}
+}
+
+func TestDeleteNetworkReloads(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddNetwork("n1n1n1n1n1n1", "backend", "bridge")
+	fake.AddNetwork("n2n2n2n2n2n2", "frontend", "bridge")
+	m := newTestModel(t, fake)
+	load(t, m, ReloadNetworks)
+	RebuildNetworkItems(m)
+
+	msg, ok := result(DeleteNetwork(m)).(models.NetworksLoadedMsg)
+	if !ok || len(msg.Networks) != 1 || msg.Networks[0].Name != "frontend" || msg.Message != "Network deleted" {
+		t.Errorf("DeleteNetwork = %+v, want only frontend left", msg)
+	}
+}
@@ -0,0 +1,161 @@
+package docker
+
+import (
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Services runs the model's commands against the Docker daemon in
+// Model.DockerClient.
+type Services struct{}
+
+var _ models.Services = Services{}
+
+func (Services) GroupByProject(containers []models.Container) ([]models.Container, []models.ComposeGroup) {
+	return GroupByProject(containers)
+}
+
+func (Services) RebuildItems(m *models.Model) {
+	RebuildItems(m)
+}
+
+func (Services) RebuildVolumeItems(m *models.Model) {
+	RebuildVolumeItems(m)
+}
+
+func (Services) RebuildImageItems(m *models.Model) {
+	RebuildImageItems(m)
+}
+
+func (Services) RebuildNetworkItems(m *models.Model) {
+	RebuildNetworkItems(m)
+}
+
+func (Services) RebuildTopItems(m *models.Model) {
+	RebuildTopItems(m)
+}
+
+func (Services) RebuildAlertItems(m *models.Model) {
+	RebuildAlertItems(m)
+}
+
+func (Services) RebuildEventItems(m *models.Model) {
+	RebuildEventItems(m)
+}
+
+func (Services) RefreshContainers(m *models.Model) tea.Cmd {
+	return RefreshContainers(m)
+}
+
+func (Services) LoadContainer(m *models.Model, id string) tea.Cmd {
+	return LoadContainer(m, id)
+}
+
+func (Services) ReloadVolumes(m *models.Model) tea.Cmd {
+	return ReloadVolumes(m)
+}
+
+func (Services) ReloadImages(m *models.Model) tea.Cmd {
+	return ReloadImages(m)
+}
+
+func (Services) ReloadNetworks(m *models.Model) tea.Cmd {
+	return ReloadNetworks(m)
+}
+
+func (Services) LoadInspect(m *models.Model) tea.Cmd {
+	return LoadInspect(m)
+}
+
+func (Services) StartContainer(m *models.Model) tea.Cmd {
+	return StartContainer(m)
+}
+
+func (Services) StopContainer(m *models.Model) tea.Cmd {
+	return StopContainer(m)
+}
+
+func (Services) RestartContainer(m *models.Model) tea.Cmd {
+	return RestartContainer(m)
+}
+
+func (Services) DeleteContainer(m *models.Model) tea.Cmd {
+	return DeleteContainer(m)
+}
+
+func (Services) DeleteVolume(m *models.Model) tea.Cmd {
+	return DeleteVolume(m)
+}
+
+func (Services) DeleteImage(m *models.Model) tea.Cmd {
+	return DeleteImage(m)
+}
+
+func (Services) DeleteNetwork(m *models.Model) tea.Cmd {
+	return DeleteNetwork(m)
+}
+
+func (Services) ExecShell(m *models.Model) tea.Cmd {
+	return ExecShell(m)
+}
+
+func (Services) OpenPortInBrowser(m *models.Model) tea.Cmd {
+	return OpenPortInBrowser(m)
+}
+
+func (Services) LoadLogs(m *models.Model) tea.Cmd {
+	return LoadLogs(m)
+}
+
+func (Services) LoadOlderLogs(m *models.Model) tea.Cmd {
+	return LoadOlderLogs(m)
+}
+
+func (Services) FollowLogs(m *models.Model) tea.Cmd {
+	return FollowLogs(m)
+}
+
+func (Services) SaveLogs(m *models.Model, path string, timestamps bool) tea.Cmd {
+	return SaveLogs(m, path, timestamps)
+}
+
+func (Services) ExportLogs(m *models.Model, path string, timestamps bool) tea.Cmd {
+	return ExportLogs(m, path, timestamps)
+}
+
+func (Services) LoadStats(m *models.Model) tea.Cmd {
+	return LoadStats(m)
+}
+
+func (Services) LoadTopStats(m *models.Model) tea.Cmd {
+	return LoadTopStats(m)
+}
+
+func (Services) LoadProjectStats(m *models.Model) tea.Cmd {
+	return LoadProjectStats(m)
+}
+
+func (Services) LoadAlertStats(m *models.Model) tea.Cmd {
+	return LoadAlertStats(m)
+}
+
+func (Services) NotifyAlert(m *models.Model, alert models.Alert) tea.Cmd {
+	return NotifyAlert(m, alert)
+}
+
+func (Services) SubscribeEvents(m *models.Model) tea.Cmd {
+	return SubscribeEvents(m)
+}
+
+func (Services) LoadEventHistory(m *models.Model, since string) tea.Cmd {
+	return LoadEventHistory(m, since)
+}
+
+func (Services) Connect(m *models.Model, c models.DockerContext) (models.DockerAPI, error) {
+	return Connect(m, c)
+}
+
+func (Services) Quit(m *models.Model) {
+	Quit(m)
+}
@@ -1,17 +1,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"gdocker/docker"
-	_ "gdocker/ui" // Import for side effects (init function)
+	"gdocker/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	m, err := docker.InitialModel()
+	readOnly := flag.Bool("read-only", false, "disable start, stop, restart, delete and exec")
+	contextName := flag.String("context", "", "Docker context or hosts profile to connect to")
+	flag.Parse()
+
+	m, err := docker.InitialModel(ui.Renderer{}, docker.Options{ReadOnly: *readOnly, Context: *contextName})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,291 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"gdocker/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxResolvedAlerts caps how many resolved alerts the alerts view keeps.
+const maxResolvedAlerts = 100
+
+// AlertMetric is the value an alert rule watches.
+type AlertMetric string
+
+const (
+	AlertMetricCPU    AlertMetric = "cpu"
+	AlertMetricMemory AlertMetric = "memory"
+)
+
+// Alert is one breach of an alert rule by one container.
+type Alert struct {
+	Rule          int // Index into Model.AlertRules
+	ContainerID   string
+	ContainerName string
+	Metric        AlertMetric
+	Threshold     float64
+	Value         float64   // Latest value while active, last value once resolved
+	Peak          float64   // Highest value seen while active
+	Since         time.Time // When the threshold was first exceeded
+	FiredAt       time.Time
+	ResolvedAt    time.Time // Zero while the alert is active
+	NotifyErr     string    // Failure of the command or webhook hook
+}
+
+// Active reports whether the container is still above the threshold.
+func (a Alert) Active() bool {
+	return a.ResolvedAt.IsZero()
+}
+
+// Message is a one-line description used in the status bar and hooks.
+func (a Alert) Message() string {
+	return fmt.Sprintf("%s %s %.1f%% ≥ %.0f%%", a.ContainerName, a.Metric, a.Value, a.Threshold)
+}
+
+// AlertStatsLoadedMsg delivers one round of samples for the alert monitor.
+type AlertStatsLoadedMsg struct {
+	Time  time.Time
+	Names map[string]string // Running containers matching any rule, by ID
+	Stats map[string]ContainerStats
+	Err   error // Listing containers failed; the round is skipped
+}
+
+// AlertTickMsg starts the next round of the alert monitor.
+type AlertTickMsg struct{}
+
+// AlertNotifiedMsg reports the outcome of an alert's command or webhook.
+type AlertNotifiedMsg struct {
+	Alert Alert
+	Err   error
+}
+
+// ValidateAlertRules checks the container globs of the configured rules.
+func ValidateAlertRules(m *Model) error {
+	for i, rule := range m.AlertRules {
+		if _, err := path.Match(rule.Container, ""); err != nil {
+			return fmt.Errorf("alert %d: bad container pattern %q", i+1, rule.Container)
+		}
+	}
+	return nil
+}
+
+// AlertRuleMatches reports whether rule watches the named container.
+func AlertRuleMatches(rule config.AlertRule, name string) bool {
+	ok, _ := path.Match(rule.Container, name)
+	return ok
+}
+
+// ActiveAlerts counts the alerts that have not resolved yet.
+func ActiveAlerts(m *Model) int {
+	n := 0
+	for _, a := range m.Alerts {
+		if a.Active() {
+			n++
+		}
+	}
+	return n
+}
+
+// alertsView is the sub-model of the alert monitor and the alerts list.
+// The monitor runs in every view; the list renders in the shared panels.
+type alertsView struct{}
+
+func (alertsView) View(m *Model, width, height int) string {
+	return m.Renderer.Details(m, width, height)
+}
+
+func (alertsView) Update(m *Model, msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case AlertTickMsg:
+		return m.Services.LoadAlertStats(m), true
+
+	case AlertStatsLoadedMsg:
+		next := alertTickCmd(m.AlertMonitorSettings().IntervalSeconds)
+		if msg.Err != nil {
+			return next, true
+		}
+		m.AlertStats = msg.Stats
+		fired := evaluateAlerts(m, msg)
+		if m.NavMode == NavAlerts {
+			m.Services.RebuildAlertItems(m)
+		}
+
+		cmds := []tea.Cmd{next}
+		for _, alert := range fired {
+			m.StatusMessage = "⚠ Alert: " + alert.Message()
+			cmds = append(cmds, m.Services.NotifyAlert(m, alert))
+		}
+		return tea.Batch(cmds...), true
+
+	case AlertNotifiedMsg:
+		if msg.Err == nil {
+			return nil, true
+		}
+		for i := range m.Alerts {
+			a := &m.Alerts[i]
+			if a.Rule == msg.Alert.Rule && a.Metric == msg.Alert.Metric &&
+				a.ContainerID == msg.Alert.ContainerID && a.FiredAt.Equal(msg.Alert.FiredAt) {
+				a.NotifyErr = msg.Err.Error()
+			}
+		}
+		m.StatusMessage = "Alert hook failed: " + msg.Err.Error()
+		return nil, true
+	}
+	return nil, false
+}
+
+func alertTickCmd(seconds int) tea.Cmd {
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return AlertTickMsg{}
+	})
+}
+
+// alertKey identifies the pending or active breach of one rule metric by one
+// container.
+func alertKey(rule int, metric AlertMetric, containerID string) string {
+	return fmt.Sprintf("%d/%s/%s", rule, metric, containerID)
+}
+
+// evaluateAlerts checks a round of samples against every rule and returns
+// the alerts that fired in this round. A breach must last the rule's For
+// duration before it fires; alerts resolve as soon as the value drops below
+// the threshold or the container stops.
+func evaluateAlerts(m *Model, msg AlertStatsLoadedMsg) []Alert {
+	if m.AlertPending == nil {
+		m.AlertPending = make(map[string]time.Time)
+	}
+
+	active := make(map[string]int)
+	for i, a := range m.Alerts {
+		if a.Active() {
+			active[alertKey(a.Rule, a.Metric, a.ContainerID)] = i
+		}
+	}
+	seen := make(map[string]bool)
+
+	var fired []Alert
+	for i, rule := range m.AlertRules {
+		for id, name := range msg.Names {
+			stats, ok := msg.Stats[id]
+			if !ok || !AlertRuleMatches(rule, name) {
+				continue
+			}
+			checks := []struct {
+				metric    AlertMetric
+				threshold float64
+				value     float64
+			}{
+				{AlertMetricCPU, rule.CPUPercent, stats.CPUPercent},
+				{AlertMetricMemory, rule.MemoryPercent, stats.MemPercent},
+			}
+			for _, check := range checks {
+				if check.threshold <= 0 {
+					continue
+				}
+				key := alertKey(i, check.metric, id)
+				seen[key] = true
+
+				if check.value < check.threshold {
+					delete(m.AlertPending, key)
+					if idx, ok := active[key]; ok {
+						m.Alerts[idx].Value = check.value
+						m.Alerts[idx].ResolvedAt = msg.Time
+					}
+					continue
+				}
+
+				if idx, ok := active[key]; ok {
+					m.Alerts[idx].Value = check.value
+					m.Alerts[idx].Peak = max(m.Alerts[idx].Peak, check.value)
+					continue
+				}
+				since, pending := m.AlertPending[key]
+				if !pending {
+					since = msg.Time
+					m.AlertPending[key] = since
+				}
+				if msg.Time.Sub(since) < rule.For {
+					continue
+				}
+
+				delete(m.AlertPending, key)
+				alert := Alert{
+					Rule:          i,
+					ContainerID:   id,
+					ContainerName: name,
+					Metric:        check.metric,
+					Threshold:     check.threshold,
+					Value:         check.value,
+					Peak:          check.value,
+					Since:         since,
+					FiredAt:       msg.Time,
+				}
+				m.Alerts = append(m.Alerts, alert)
+				fired = append(fired, alert)
+			}
+		}
+	}
+
+	// Containers that stopped or no longer match end their breaches. A
+	// container whose sample failed this round keeps its state.
+	for key, idx := range active {
+		if seen[key] {
+			continue
+		}
+		id := m.Alerts[idx].ContainerID
+		_, running := msg.Names[id]
+		_, sampled := msg.Stats[id]
+		if !running || sampled {
+			m.Alerts[idx].ResolvedAt = msg.Time
+		}
+	}
+	for key := range m.AlertPending {
+		if !seen[key] {
+			delete(m.AlertPending, key)
+		}
+	}
+
+	pruneResolvedAlerts(m)
+	return fired
+}
+
+// pruneResolvedAlerts drops the oldest resolved alerts past the cap.
+func pruneResolvedAlerts(m *Model) {
+	resolved := 0
+	for _, a := range m.Alerts {
+		if !a.Active() {
+			resolved++
+		}
+	}
+	if resolved <= maxResolvedAlerts {
+		return
+	}
+
+	drop := resolved - maxResolvedAlerts
+	kept := m.Alerts[:0]
+	for _, a := range m.Alerts {
+		if !a.Active() && drop > 0 {
+			drop--
+			continue
+		}
+		kept = append(kept, a)
+	}
+	m.Alerts = kept
+}
+
+// clearResolvedAlerts removes every resolved alert from the alerts view.
+func clearResolvedAlerts(m *Model) int {
+	kept := m.Alerts[:0]
+	for _, a := range m.Alerts {
+		if a.Active() {
+			kept = append(kept, a)
+		}
+	}
+	cleared := len(m.Alerts) - len(kept)
+	m.Alerts = kept
+	return cleared
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"gdocker/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Confirmation is a destructive action waiting in a dialog until the user
+// confirms it with y or drops it with n or esc.
+type Confirmation struct {
+	Title      string   // The question, e.g. "Delete 2 volumes?"
+	Items      []string // What will be removed
+	Dependents []string // What else is affected, e.g. containers using a volume
+	confirm    func(*Model) tea.Cmd
+}
+
+// ValidateProtected checks the globs of the protected names. A pattern
+// that is not a valid glob still protects the exact name it spells.
+func ValidateProtected(m *Model) error {
+	for _, pattern := range m.SafetySettings().Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad protected pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// IsProtected reports whether a protected pattern matches one of the
+// names of item.
+func IsProtected(m *Model, item ListItem) bool {
+	for _, pattern := range m.SafetySettings().Protected {
+		for _, name := range itemNames(item) {
+			if ok, err := path.Match(pattern, name); ok || (err != nil && pattern == name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// itemNames are the names a protected pattern is matched against. Images
+// go by their short ID and each of their tags.
+func itemNames(item ListItem) []string {
+	switch {
+	case item.IsContainer:
+		return []string{item.Container.Name}
+	case item.IsVolume:
+		return []string{item.Volume.Name}
+	case item.IsImage:
+		return append([]string{item.Image.ID}, item.Image.RepoTags...)
+	case item.IsNetwork:
+		return []string{item.Network.Name}
+	}
+	return nil
+}
+
+// itemName is how an item is called in dialogs and status messages.
+func itemName(item ListItem) string {
+	if item.IsImage {
+		if len(item.Image.RepoTags) > 0 && item.Image.RepoTags[0] != "<none>:<none>" {
+			return item.Image.RepoTags[0]
+		}
+		return item.Image.ID
+	}
+	if names := itemNames(item); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// confirmsDelete reports whether the safety policy asks before deleting
+// from the current list.
+func confirmsDelete(m *Model) bool {
+	switch m.SafetySettings().ConfirmDelete {
+	case config.ConfirmNever:
+		return false
+	case config.ConfirmOnlyForVolumes:
+		return m.NavMode == NavVolumes
+	}
+	return true
+}
+
+// deleteConfirmation asks before deleting targets. Confirming deletes
+// exactly these items, even if the list was refreshed in the meantime.
+func deleteConfirmation(m *Model, targets []ListItem) *Confirmation {
+	resource, _ := NavResource(m.NavMode)
+	noun := resource.String()
+	title := fmt.Sprintf("Delete %d %s?", len(targets), noun)
+	if len(targets) == 1 {
+		title = fmt.Sprintf("Delete %s %s?", strings.TrimSuffix(noun, "s"), itemName(targets[0]))
+	}
+
+	names := make([]string, len(targets))
+	for i, item := range targets {
+		names[i] = itemName(item)
+	}
+
+	return &Confirmation{
+		Title:      title,
+		Items:      names,
+		Dependents: deleteDependents(m, targets),
+		confirm: func(m *Model) tea.Cmd {
+			clearMarks(m)
+			for _, item := range targets {
+				m.setMark(item, true)
+			}
+			if len(m.MarkedItems()) != len(targets) {
+				clearMarks(m)
+				m.StatusMessage = "The list changed, nothing was deleted"
+				return nil
+			}
+			return deleteTargets(m)
+		},
+	}
+}
+
+// deleteDependents describes what else deleting targets affects: running
+// containers are killed, and volumes, images and networks may still be in
+// use by containers.
+func deleteDependents(m *Model, targets []ListItem) []string {
+	var lines []string
+	for _, item := range targets {
+		if item.IsContainer {
+			if item.Container.State == "running" {
+				lines = append(lines, item.Container.Name+" is running and will be killed")
+			}
+			continue
+		}
+
+		var users []string
+		for _, c := range m.Containers {
+			var uses bool
+			switch {
+			case item.IsVolume:
+				uses = slices.Contains(c.Volumes, item.Volume.Name)
+			case item.IsImage:
+				uses = c.ImageID != "" && c.ImageID == item.Image.ID
+			case item.IsNetwork:
+				uses = slices.Contains(c.Networks, item.Network.Name)
+			}
+			if uses {
+				users = append(users, fmt.Sprintf("%s (%s)", c.Name, c.State))
+			}
+		}
+		if len(users) > 0 {
+			lines = append(lines, fmt.Sprintf("%s is used by %s", itemName(item), strings.Join(users, ", ")))
+		}
+	}
+	return lines
+}
+
+// updateConfirm handles a key while a confirmation dialog is open.
+func updateConfirm(m *Model, key string) tea.Cmd {
+	switch {
+	case key == "y":
+		confirm := m.Confirm.confirm
+		m.Input = InputNormal
+		m.Confirm = nil
+		return confirm(m)
+	case key == "n" || slices.Contains(m.KeyBindings.Views.Back, key):
+		m.Input = InputNormal
+		m.Confirm = nil
+		m.StatusMessage = "Cancelled"
+	case slices.Contains(m.KeyBindings.General.ForceQuit, key):
+		_, cmd := handleForceQuit(m)
+		return cmd
+	}
+	return nil
+}
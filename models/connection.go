@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Resource is one of the lists loaded from the daemon.
+type Resource int
+
+const (
+	ResourceContainers Resource = iota
+	ResourceVolumes
+	ResourceImages
+	ResourceNetworks
+)
+
+// AllResources are loaded concurrently on startup and after a reconnect.
+var AllResources = []Resource{ResourceContainers, ResourceVolumes, ResourceImages, ResourceNetworks}
+
+func (r Resource) String() string {
+	switch r {
+	case ResourceVolumes:
+		return "volumes"
+	case ResourceImages:
+		return "images"
+	case ResourceNetworks:
+		return "networks"
+	default:
+		return "containers"
+	}
+}
+
+// ResourceFailedMsg reports that loading a list failed. Unreachable is set
+// when the daemon could not be reached at all, rather than rejecting the
+// request.
+type ResourceFailedMsg struct {
+	Client      DockerAPI // Daemon the load was sent to
+	Resource    Resource
+	Err         error
+	Unreachable bool
+}
+
+// DaemonReconnectMsg retries the connection to an unreachable daemon.
+type DaemonReconnectMsg struct{}
+
+// NavResource is the list shown in a navigation mode. ok is false for
+// views built from other state, like alerts and events.
+func NavResource(mode NavigationMode) (Resource, bool) {
+	switch mode {
+	case NavContainers, NavTop:
+		return ResourceContainers, true
+	case NavVolumes:
+		return ResourceVolumes, true
+	case NavImages:
+		return ResourceImages, true
+	case NavNetworks:
+		return ResourceNetworks, true
+	}
+	return 0, false
+}
+
+// StartLoading marks every list as loading, before the first load.
+func (s *DockerState) StartLoading() {
+	s.Loading = make(map[Resource]bool)
+	for _, r := range AllResources {
+		s.Loading[r] = true
+	}
+}
+
+// resourceLoaded records a successful load. A load while disconnected means
+// the daemon is back: the other lists and the event stream are restored.
+func resourceLoaded(m *Model, r Resource) tea.Cmd {
+	delete(m.Loading, r)
+	delete(m.LoadErrors, r)
+	if !m.Disconnected {
+		return nil
+	}
+
+	m.Disconnected = false
+	m.Reconnecting = false
+	m.ReconnectBackoff = 0
+	m.StatusMessage = "Reconnected to Docker"
+	var cmds []tea.Cmd
+	for _, other := range AllResources {
+		if other != r {
+			cmds = append(cmds, reloadResource(m, other))
+		}
+	}
+	if m.EventStream == nil {
+		m.EventBackoff = 0
+		cmds = append(cmds, m.Services.SubscribeEvents(m))
+	}
+	return tea.Batch(cmds...)
+}
+
+// resourceFailed shows a failed load in place of its list. An unreachable
+// daemon switches to the disconnected state and starts retrying.
+func resourceFailed(m *Model, msg ResourceFailedMsg) tea.Cmd {
+	delete(m.Loading, msg.Resource)
+	if m.LoadErrors == nil {
+		m.LoadErrors = make(map[Resource]string)
+	}
+	m.LoadErrors[msg.Resource] = msg.Err.Error()
+
+	if !msg.Unreachable {
+		m.StatusMessage = fmt.Sprintf("Failed to load %s: %v", msg.Resource, msg.Err)
+		return nil
+	}
+	if m.Disconnected && !m.Reconnecting {
+		// The other lists of the same attempt fail too; one retry is enough.
+		return nil
+	}
+
+	m.Disconnected = true
+	m.Reconnecting = false
+	stopEventStream(m)
+	m.ReconnectBackoff = nextEventBackoff(m.ReconnectBackoff)
+	m.StatusMessage = fmt.Sprintf("Docker is unreachable; retrying in %s", m.ReconnectBackoff)
+	return tea.Tick(m.ReconnectBackoff, func(time.Time) tea.Msg {
+		return DaemonReconnectMsg{}
+	})
+}
+
+// reconnect tries the daemon again by listing the containers. Success is
+// handled by resourceLoaded, failure schedules the next attempt.
+func reconnect(m *Model) tea.Cmd {
+	if !m.Disconnected {
+		return nil
+	}
+	m.Reconnecting = true
+	m.StatusMessage = "Reconnecting to Docker..."
+	return m.Services.RefreshContainers(m)
+}
+
+// reloadResource loads one list again.
+func reloadResource(m *Model, r Resource) tea.Cmd {
+	switch r {
+	case ResourceVolumes:
+		return m.Services.ReloadVolumes(m)
+	case ResourceImages:
+		return m.Services.ReloadImages(m)
+	case ResourceNetworks:
+		return m.Services.ReloadNetworks(m)
+	default:
+		return m.Services.RefreshContainers(m)
+	}
+}
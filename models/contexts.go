@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DockerContext is a daemon GDocker can connect to: a Docker CLI context or
+// a hosts profile from the config.
+type DockerContext struct {
+	Name        string
+	Host        string // Endpoint; empty uses DOCKER_HOST or the default socket
+	Description string
+	Source      string // "docker" for CLI contexts, "config" for hosts profiles
+	TLSDir      string // Holds ca.pem, cert.pem and key.pem when the endpoint uses TLS
+}
+
+// DefaultContext is the daemon GDocker connects to without a context: the
+// configured host, or DOCKER_HOST and the default socket.
+const DefaultContext = "default"
+
+// findContext looks up a context by name.
+func findContext(m *Model, name string) (DockerContext, bool) {
+	for _, c := range m.Contexts {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return DockerContext{}, false
+}
+
+// fromOtherDaemon reports whether a result came from the daemon of an
+// earlier context. Those results are dropped.
+func fromOtherDaemon(m *Model, cli DockerAPI) bool {
+	return cli != nil && cli != m.DockerClient
+}
+
+// cmdContext lists the contexts, or switches to the named one.
+func cmdContext(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		names := make([]string, len(m.Contexts))
+		for i, c := range m.Contexts {
+			names[i] = c.Name
+			if c.Name == m.Context {
+				names[i] += " (active)"
+			}
+		}
+		m.StatusMessage = "Contexts: " + strings.Join(names, ", ")
+		return nil
+	}
+
+	c, ok := findContext(m, args[0])
+	if !ok {
+		m.StatusMessage = fmt.Sprintf("Unknown context %q; :context lists them", args[0])
+		return nil
+	}
+	if c.Name == m.Context {
+		m.StatusMessage = "Already connected to " + c.Name
+		return nil
+	}
+	return switchContext(m, c)
+}
+
+// switchContext connects to another daemon. Everything tied to the
+// previous one is dropped and the lists load again, as on startup.
+func switchContext(m *Model, c DockerContext) tea.Cmd {
+	cli, err := m.Services.Connect(m, c)
+	if err != nil {
+		m.StatusMessage = fmt.Sprintf("Cannot connect to %s: %v", c.Name, err)
+		return nil
+	}
+
+	m.CancelOperations()
+	if m.LogExport != nil {
+		m.LogExport.Cancel()
+	}
+	stopLogStream(m)
+	stopStatsStream(m)
+	stopEventStream(m)
+	if m.DockerClient != nil {
+		m.DockerClient.Close()
+	}
+	m.DockerClient = cli
+	m.Context = c.Name
+	resetDaemonState(m)
+
+	m.StartLoading()
+	m.StatusMessage = "Switched to context " + c.Name
+	cmds := []tea.Cmd{resyncAll(m), m.Services.SubscribeEvents(m)}
+	if m.NavMode == NavTop {
+		// The overview's refresh loop ended with the old generation; the
+		// next round samples the new daemon once its containers are listed.
+		m.TopLoading = true
+		cmds = append(cmds, topTickCmd(m.TopSettings().RefreshSeconds, m.TopGeneration))
+	}
+	return tea.Batch(cmds...)
+}
+
+// resetDaemonState clears everything learned from the previous daemon.
+func resetDaemonState(m *Model) {
+	m.Containers = []Container{}
+	m.Standalone = []Container{}
+	m.Projects = []ComposeGroup{}
+	m.Volumes = []Volume{}
+	m.Images = []Image{}
+	m.Networks = []Network{}
+	m.EnvCache = NewEnvCache()
+	m.LoadErrors = nil
+	m.Disconnected = false
+	m.Reconnecting = false
+	m.ReconnectBackoff = 0
+
+	m.Items = []ListItem{}
+	m.Cursor = 0
+	m.Marked = nil
+	m.ViewMode = ViewDetails
+	m.InspectData = ""
+
+	m.Logs = nil
+	m.VisibleLogs = nil
+	m.LogTargets = nil
+	m.LogProject = ""
+	m.Stats = nil
+	m.StatsHistory = nil
+	m.ProjectStatsName = ""
+	m.ProjectStats = nil
+	m.TopStats = nil
+	m.TopGeneration++ // Drops overview rounds still sampling the previous daemon
+
+	m.Alerts = nil
+	m.AlertPending = nil
+	m.AlertStats = nil
+	m.EventLog = nil
+	m.EventSince = ""
+	m.EventBackoff = 0
+	m.LastEventTime = time.Time{}
+}
@@ -0,0 +1,68 @@
+package models
+
+import "sync"
+
+// envEntry is the cached environment of one container.
+type envEntry struct {
+	created int64
+	state   string
+	env     []string
+}
+
+// EnvCache keeps the environment read by ContainerInspect, keyed by
+// container ID. An entry stays valid while the container's creation time
+// and state are unchanged, so a refresh only inspects new or changed
+// containers. Each model has its own for the daemon it is connected to; a
+// nil cache caches nothing. It is safe for concurrent use.
+type EnvCache struct {
+	mu      sync.Mutex
+	entries map[string]envEntry
+}
+
+// NewEnvCache returns an empty cache.
+func NewEnvCache() *EnvCache {
+	return &EnvCache{entries: make(map[string]envEntry)}
+}
+
+// Get returns the environment cached for the container, if it is still
+// valid.
+func (c *EnvCache) Get(id string, created int64, state string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	if !ok || e.created != created || e.state != state {
+		return nil, false
+	}
+	return e.env, true
+}
+
+// Put caches the environment of a container in the given state.
+func (c *EnvCache) Put(id string, created int64, state string, env []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[id] = envEntry{created: created, state: state, env: env}
+}
+
+// Retain drops the entries of containers that no longer exist.
+func (c *EnvCache) Retain(containers []Container) {
+	if c == nil {
+		return
+	}
+	keep := make(map[string]bool, len(containers))
+	for _, ct := range containers {
+		keep[ct.ID] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.entries {
+		if !keep[id] {
+			delete(c.entries, id)
+		}
+	}
+}
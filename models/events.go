@@ -0,0 +1,313 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// eventBatchWindow groups the bursts of events Docker sends for one
+	// operation, such as compose up, into a single update.
+	eventBatchWindow = 100 * time.Millisecond
+
+	minEventBackoff = 2 * time.Second
+	maxEventBackoff = time.Minute
+)
+
+// DockerEvent is one event reported by the daemon.
+type DockerEvent struct {
+	Time       time.Time
+	Type       string // container, image, volume, network, ...
+	Action     string
+	ActorID    string
+	Attributes map[string]string
+}
+
+// Name is the actor's name when Docker reports one, otherwise its ID.
+func (e DockerEvent) Name() string {
+	if name := e.Attributes["name"]; name != "" {
+		return name
+	}
+	return e.ActorID
+}
+
+// EventStream is the daemon event subscription. Producers push events into
+// Events and report the terminal error on Done before closing Events.
+type EventStream struct {
+	Events <-chan DockerEvent
+	Done   <-chan error
+	Cancel context.CancelFunc
+}
+
+// DockerEventsMsg delivers a batch of events read from an EventStream.
+type DockerEventsMsg struct {
+	Stream *EventStream
+	Events []DockerEvent
+}
+
+// EventStreamEndedMsg is sent once an EventStream has dropped.
+type EventStreamEndedMsg struct {
+	Stream *EventStream
+	Err    error
+}
+
+// EventsReconnectMsg retries the event subscription after a drop.
+type EventsReconnectMsg struct{}
+
+// ContainerUpdatedMsg carries the current state of one container after an
+// event. Container is nil when it no longer exists.
+type ContainerUpdatedMsg struct {
+	Client    DockerAPI // Daemon the container came from
+	ID        string
+	Container *Container
+}
+
+// Next waits for the next batch of events.
+func (s *EventStream) Next() tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-s.Events
+		if !ok {
+			return EventStreamEndedMsg{Stream: s, Err: <-s.Done}
+		}
+
+		batch := []DockerEvent{event}
+		window := time.After(eventBatchWindow)
+		for {
+			select {
+			case event, ok := <-s.Events:
+				if !ok {
+					return DockerEventsMsg{Stream: s, Events: batch}
+				}
+				batch = append(batch, event)
+			case <-window:
+				return DockerEventsMsg{Stream: s, Events: batch}
+			}
+		}
+	}
+}
+
+// eventsView is the sub-model of the daemon event subscription and the
+// events timeline. Events also keep the resource lists current while the
+// stream is connected.
+type eventsView struct{}
+
+func (eventsView) View(m *Model, width, height int) string {
+	return m.Renderer.Events(m, width, height)
+}
+
+func (eventsView) Update(m *Model, msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case DockerEventsMsg:
+		if msg.Stream != m.EventStream {
+			return nil, true
+		}
+		m.EventBackoff = 0
+		for _, event := range msg.Events {
+			if event.Time.After(m.LastEventTime) {
+				m.LastEventTime = event.Time
+			}
+		}
+		cmd := applyEvents(m, msg.Events)
+		appendEventLog(m, msg.Events)
+		if m.NavMode == NavEvents {
+			m.Services.RebuildEventItems(m)
+		}
+		return tea.Batch(cmd, msg.Stream.Next()), true
+
+	case EventHistoryLoadedMsg:
+		m.EventLoading = false
+		if msg.Err != nil {
+			m.StatusMessage = "Failed to load events: " + msg.Err.Error()
+			return nil, true
+		}
+		m.EventSince = msg.Since
+		mergeEventHistory(m, msg)
+		if m.NavMode == NavEvents {
+			m.Cursor = len(m.EventLog)
+			m.Services.RebuildEventItems(m)
+		}
+		m.StatusMessage = fmt.Sprintf("Loaded %d events since %s", len(msg.Events), msg.Since)
+		return nil, true
+
+	case EventStreamEndedMsg:
+		if msg.Stream != m.EventStream {
+			return nil, true
+		}
+		m.EventStream = nil
+		if m.Disconnected {
+			// Resubscribed once the daemon is reachable again
+			return nil, true
+		}
+		m.EventBackoff = nextEventBackoff(m.EventBackoff)
+		m.StatusMessage = fmt.Sprintf("Docker events disconnected, polling; retrying in %s", m.EventBackoff)
+		return tea.Tick(m.EventBackoff, func(time.Time) tea.Msg {
+			return EventsReconnectMsg{}
+		}), true
+
+	case EventsReconnectMsg:
+		if m.EventStream != nil || m.Disconnected {
+			return nil, true
+		}
+		cmd := m.Services.SubscribeEvents(m)
+		return tea.Batch(cmd, resyncAll(m)), true
+	}
+	return nil, false
+}
+
+// stopEventStream cancels the event subscription, if any.
+func stopEventStream(m *Model) {
+	if m.EventStream != nil {
+		m.EventStream.Cancel()
+		m.EventStream = nil
+	}
+}
+
+// containerEventActions are the container events that change what the
+// lists show. Exec, attach and similar events are ignored.
+var containerEventActions = map[string]bool{
+	"create":  true,
+	"start":   true,
+	"restart": true,
+	"stop":    true,
+	"die":     true,
+	"kill":    true,
+	"pause":   true,
+	"unpause": true,
+	"rename":  true,
+	"update":  true,
+	"destroy": true,
+	"oom":     true,
+}
+
+// applyEvents turns a batch of events into targeted updates: containers are
+// reloaded one by one, volumes, images and networks as a list.
+func applyEvents(m *Model, events []DockerEvent) tea.Cmd {
+	var (
+		containers                = make(map[string]bool)
+		removed                   = make(map[string]bool)
+		volumes, images, networks bool
+	)
+	for _, e := range events {
+		switch e.Type {
+		case "container":
+			action := e.Action
+			if strings.HasPrefix(action, "health_status") {
+				action = "update"
+			}
+			if action == "commit" {
+				images = true
+			}
+			if !containerEventActions[action] {
+				continue
+			}
+			id := shortID(e.ActorID)
+			if action == "destroy" {
+				removed[id] = true
+				delete(containers, id)
+			} else if !removed[id] {
+				containers[id] = true
+			}
+		case "image":
+			images = true
+		case "volume":
+			volumes = volumes || e.Action == "create" || e.Action == "destroy" || e.Action == "prune"
+		case "network":
+			networks = networks || e.Action == "create" || e.Action == "destroy" || e.Action == "remove" || e.Action == "prune"
+		}
+	}
+
+	if len(removed) > 0 {
+		kept := make([]Container, 0, len(m.Containers))
+		for _, c := range m.Containers {
+			if !removed[c.ID] {
+				kept = append(kept, c)
+			}
+		}
+		setContainers(m, kept)
+	}
+
+	var cmds []tea.Cmd
+	for id := range containers {
+		cmds = append(cmds, m.Services.LoadContainer(m, id))
+	}
+	if volumes {
+		cmds = append(cmds, m.Services.ReloadVolumes(m))
+	}
+	if images {
+		cmds = append(cmds, m.Services.ReloadImages(m))
+	}
+	if networks {
+		cmds = append(cmds, m.Services.ReloadNetworks(m))
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateContainer replaces, adds or removes one container.
+func updateContainer(m *Model, id string, c *Container) {
+	containers := make([]Container, 0, len(m.Containers)+1)
+	found := false
+	for _, existing := range m.Containers {
+		if existing.ID != id {
+			containers = append(containers, existing)
+			continue
+		}
+		found = true
+		if c != nil {
+			containers = append(containers, *c)
+		}
+	}
+	if !found && c != nil {
+		containers = append(containers, *c)
+	}
+	setContainers(m, containers)
+}
+
+// setContainers replaces the container list, keeping expanded projects
+// expanded, and rebuilds the list shown in the current navigation mode.
+func setContainers(m *Model, containers []Container) {
+	expandedProjects := make(map[string]bool)
+	for _, p := range m.Projects {
+		if p.Expanded {
+			expandedProjects[p.Name] = true
+		}
+	}
+
+	m.Containers = containers
+	m.Standalone, m.Projects = m.Services.GroupByProject(containers)
+
+	for i := range m.Projects {
+		if expandedProjects[m.Projects[i].Name] {
+			m.Projects[i].Expanded = true
+		}
+	}
+
+	switch m.NavMode {
+	case NavContainers:
+		m.Services.RebuildItems(m)
+	case NavTop:
+		m.Services.RebuildTopItems(m)
+	}
+}
+
+// resyncAll reloads every resource list, catching up on events missed
+// while the subscription was down.
+func resyncAll(m *Model) tea.Cmd {
+	return tea.Batch(m.Services.RefreshContainers(m), m.Services.ReloadVolumes(m), m.Services.ReloadImages(m), m.Services.ReloadNetworks(m))
+}
+
+// nextEventBackoff doubles the reconnect delay up to maxEventBackoff.
+func nextEventBackoff(current time.Duration) time.Duration {
+	return min(max(current*2, minEventBackoff), maxEventBackoff)
+}
+
+// shortID is the 12-character form the lists use for IDs.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +46,15 @@ func buildKeyHandlerMap(m *Model) map[string]KeyHandler {
 	for _, key := range kb.Navigation.SwitchNetwork {
 		handlers[key] = handleSwitchNetwork
 	}
+	for _, key := range kb.Navigation.SwitchTop {
+		handlers[key] = handleSwitchTop
+	}
+	for _, key := range kb.Navigation.SwitchAlerts {
+		handlers[key] = handleSwitchAlerts
+	}
+	for _, key := range kb.Navigation.SwitchEvents {
+		handlers[key] = handleSwitchEvents
+	}
 
 	// Container action handlers
 	for _, key := range kb.Container.Restart {
@@ -76,6 +88,17 @@ func buildKeyHandlerMap(m *Model) map[string]KeyHandler {
 		handlers[key] = handleOpenPort
 	}
 
+	// Selection handlers
+	for _, key := range kb.Selection.Toggle {
+		handlers[key] = handleToggleMark
+	}
+	for _, key := range kb.Selection.MarkAll {
+		handlers[key] = handleMarkAll
+	}
+	for _, key := range kb.Selection.Invert {
+		handlers[key] = handleInvertMarks
+	}
+
 	// Command and search handlers
 	for _, key := range kb.Commands.Enter {
 		handlers[key] = handleCommandMode
@@ -92,6 +115,62 @@ func buildKeyHandlerMap(m *Model) map[string]KeyHandler {
 	for _, key := range kb.Logs.Follow {
 		handlers[key] = handleToggleLogFollow
 	}
+	for _, key := range kb.Logs.StreamFilter {
+		handlers[key] = handleCycleLogStreams
+	}
+	for _, key := range kb.Logs.CycleSource {
+		handlers[key] = handleCycleLogSource
+	}
+	for _, key := range kb.Logs.LoadOlder {
+		handlers[key] = handleLoadOlderLogs
+	}
+	for _, key := range kb.Logs.JSONView {
+		handlers[key] = handleToggleJSONView
+	}
+	for _, key := range kb.Logs.Expand {
+		handlers[key] = handleToggleLogDetail
+	}
+	for _, key := range kb.Logs.LevelFilter {
+		handlers[key] = handleCycleMinLevel
+	}
+	for _, key := range kb.Logs.SmartCase {
+		handlers[key] = handleToggleSmartCase
+	}
+	for _, key := range kb.Logs.FilterMode {
+		handlers[key] = handleToggleSearchFilter
+	}
+
+	// Overview handlers. Their keys may be shared with other views (enter
+	// also expands projects and opens ports), so they fall back to the
+	// handler they replace outside the overview.
+	for _, key := range kb.Top.Jump {
+		handlers[key] = inTopView(handleTopJump, handlers[key])
+	}
+	for _, key := range kb.Top.SortNext {
+		handlers[key] = inTopView(handleTopSortNext, handlers[key])
+	}
+	for _, key := range kb.Top.SortPrev {
+		handlers[key] = inTopView(handleTopSortPrev, handlers[key])
+	}
+	for _, key := range kb.Top.SortReverse {
+		handlers[key] = inTopView(handleTopSortReverse, handlers[key])
+	}
+
+	// Alerts view handlers, falling back the same way
+	for _, key := range kb.Alerts.Jump {
+		handlers[key] = inAlertsView(handleAlertJump, handlers[key])
+	}
+	for _, key := range kb.Alerts.Clear {
+		handlers[key] = inAlertsView(handleClearAlerts, handlers[key])
+	}
+
+	// Events timeline handlers, falling back the same way
+	for _, key := range kb.Events.Jump {
+		handlers[key] = inEventsView(handleEventJump, handlers[key])
+	}
+	for _, key := range kb.Events.CycleType {
+		handlers[key] = inEventsView(handleCycleEventType, handlers[key])
+	}
 
 	// View handlers
 	for _, key := range kb.Views.Back {
@@ -110,7 +189,13 @@ func buildKeyHandlerMap(m *Model) map[string]KeyHandler {
 
 func handleNavigationUp(m *Model) (Model, tea.Cmd) {
 	switch m.ViewMode {
-	case ViewLogs, ViewInspect:
+	case ViewLogs:
+		// Scrolling past the top pages in older history
+		if m.LogScroll <= 0 {
+			return handleLoadOlderLogs(m)
+		}
+		m.LogScroll--
+	case ViewInspect:
 		if m.LogScroll > 0 {
 			m.LogScroll--
 		}
@@ -129,7 +214,7 @@ func handleNavigationUp(m *Model) (Model, tea.Cmd) {
 func handleNavigationDown(m *Model) (Model, tea.Cmd) {
 	switch m.ViewMode {
 	case ViewLogs, ViewInspect:
-		maxLines := len(m.Logs)
+		maxLines := len(m.VisibleLogs)
 		if m.ViewMode == ViewInspect {
 			maxLines = len(strings.Split(m.InspectData, "\n"))
 		}
@@ -161,7 +246,7 @@ func handleNavigationTop(m *Model) (Model, tea.Cmd) {
 
 func handleNavigationBottom(m *Model) (Model, tea.Cmd) {
 	if m.ViewMode == ViewLogs || m.ViewMode == ViewInspect {
-		maxLines := len(m.Logs) - 1
+		maxLines := len(m.VisibleLogs) - 1
 		if m.ViewMode == ViewInspect {
 			maxLines = len(strings.Split(m.InspectData, "\n")) - 1
 		}
@@ -177,7 +262,7 @@ func handleToggleExpand(m *Model) (Model, tea.Cmd) {
 	if m.ViewMode == ViewDetails && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsProject {
 		idx := m.Items[m.Cursor].Index
 		m.Projects[idx].Expanded = !m.Projects[idx].Expanded
-		RebuildItemsFunc(m)
+		m.Services.RebuildItems(m)
 	}
 	// Note: "enter" key in ports view is handled separately by handleOpenPort
 	return *m, nil
@@ -188,7 +273,9 @@ func handleSwitchContainer(m *Model) (Model, tea.Cmd) {
 		m.NavMode = NavContainers
 		m.ViewMode = ViewDetails
 		m.Cursor = 0
-		RebuildItemsFunc(m)
+		stopLogStream(m)
+		stopStatsStream(m)
+		m.Services.RebuildItems(m)
 	}
 	return *m, nil
 }
@@ -198,7 +285,9 @@ func handleSwitchVolume(m *Model) (Model, tea.Cmd) {
 		m.NavMode = NavVolumes
 		m.ViewMode = ViewDetails
 		m.Cursor = 0
-		RebuildVolumeItemsFunc(m)
+		stopLogStream(m)
+		stopStatsStream(m)
+		m.Services.RebuildVolumeItems(m)
 	}
 	return *m, nil
 }
@@ -208,7 +297,9 @@ func handleSwitchImage(m *Model) (Model, tea.Cmd) {
 		m.NavMode = NavImages
 		m.ViewMode = ViewDetails
 		m.Cursor = 0
-		RebuildImageItemsFunc(m)
+		stopLogStream(m)
+		stopStatsStream(m)
+		m.Services.RebuildImageItems(m)
 	}
 	return *m, nil
 }
@@ -218,36 +309,337 @@ func handleSwitchNetwork(m *Model) (Model, tea.Cmd) {
 		m.NavMode = NavNetworks
 		m.ViewMode = ViewDetails
 		m.Cursor = 0
-		RebuildNetworkItemsFunc(m)
+		stopLogStream(m)
+		stopStatsStream(m)
+		m.Services.RebuildNetworkItems(m)
+	}
+	return *m, nil
+}
+
+func handleSwitchTop(m *Model) (Model, tea.Cmd) {
+	if m.NavMode == NavTop {
+		return *m, nil
+	}
+	m.NavMode = NavTop
+	m.ViewMode = ViewDetails
+	m.Cursor = 0
+	stopLogStream(m)
+	stopStatsStream(m)
+
+	// A new generation lets any round still in flight from an earlier visit
+	// end without starting a second refresh loop.
+	m.TopGeneration++
+	m.TopLoading = true
+	m.Services.RebuildTopItems(m)
+	return *m, m.Services.LoadTopStats(m)
+}
+
+// Overview handlers
+
+// inTopView runs handler in the overview table and fallback elsewhere.
+func inTopView(handler, fallback KeyHandler) KeyHandler {
+	return func(m *Model) (Model, tea.Cmd) {
+		if m.NavMode == NavTop && m.ViewMode == ViewDetails {
+			return handler(m)
+		}
+		if fallback != nil {
+			return fallback(m)
+		}
+		return *m, nil
+	}
+}
+
+// handleTopJump opens the selected container in the containers list.
+func handleTopJump(m *Model) (Model, tea.Cmd) {
+	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+		return *m, nil
+	}
+	jumpToContainer(m, *m.Items[m.Cursor].Container)
+	return *m, nil
+}
+
+// jumpToContainer selects target in the containers list, expanding its
+// compose project if needed.
+func jumpToContainer(m *Model, target Container) {
+	m.NavMode = NavContainers
+	m.ViewMode = ViewDetails
+	for i := range m.Projects {
+		if m.Projects[i].Name == target.Project {
+			m.Projects[i].Expanded = true
+		}
+	}
+	m.Services.RebuildItems(m)
+
+	m.Cursor = 0
+	for i, item := range m.Items {
+		if item.IsContainer && item.Container.ID == target.ID {
+			m.Cursor = i
+			break
+		}
+	}
+}
+
+// handleTopSortNext moves the sort to the next column. Names sort
+// ascending and numbers descending, busiest first.
+func handleTopSortNext(m *Model) (Model, tea.Cmd) {
+	column := (m.TopSort + 1) % topColumnCount
+	setTopSort(m, column, column == TopColumnName)
+	return *m, nil
+}
+
+func handleTopSortPrev(m *Model) (Model, tea.Cmd) {
+	column := (m.TopSort + topColumnCount - 1) % topColumnCount
+	setTopSort(m, column, column == TopColumnName)
+	return *m, nil
+}
+
+func handleTopSortReverse(m *Model) (Model, tea.Cmd) {
+	setTopSort(m, m.TopSort, !m.TopSortAsc)
+	return *m, nil
+}
+
+// setTopSort re-sorts the overview, keeping the cursor on the same container.
+func setTopSort(m *Model, column TopColumn, asc bool) {
+	m.TopSort = column
+	m.TopSortAsc = asc
+	m.Services.RebuildTopItems(m)
+
+	order := "descending"
+	if asc {
+		order = "ascending"
+	}
+	m.StatusMessage = "Sorted by " + column.String() + ", " + order
+}
+
+func handleSwitchAlerts(m *Model) (Model, tea.Cmd) {
+	if m.NavMode != NavAlerts {
+		m.NavMode = NavAlerts
+		m.ViewMode = ViewDetails
+		m.Cursor = 0
+		stopLogStream(m)
+		stopStatsStream(m)
+		m.Services.RebuildAlertItems(m)
+	}
+	if len(m.AlertRules) == 0 {
+		m.StatusMessage = "No alert rules configured (see alerts: in config.yaml)"
 	}
 	return *m, nil
 }
 
+// Alerts view handlers
+
+// inAlertsView runs handler in the alerts view and fallback elsewhere.
+func inAlertsView(handler, fallback KeyHandler) KeyHandler {
+	return func(m *Model) (Model, tea.Cmd) {
+		if m.NavMode == NavAlerts && m.ViewMode == ViewDetails {
+			return handler(m)
+		}
+		if fallback != nil {
+			return fallback(m)
+		}
+		return *m, nil
+	}
+}
+
+// handleAlertJump opens the container of the selected alert.
+func handleAlertJump(m *Model) (Model, tea.Cmd) {
+	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsAlert {
+		return *m, nil
+	}
+	alert := *m.Items[m.Cursor].Alert
+	for _, c := range m.Containers {
+		if c.ID == alert.ContainerID {
+			jumpToContainer(m, c)
+			return *m, nil
+		}
+	}
+	m.StatusMessage = fmt.Sprintf("Container %s no longer exists", alert.ContainerName)
+	return *m, nil
+}
+
+func handleClearAlerts(m *Model) (Model, tea.Cmd) {
+	cleared := clearResolvedAlerts(m)
+	if m.NavMode == NavAlerts {
+		m.Services.RebuildAlertItems(m)
+	}
+	m.StatusMessage = fmt.Sprintf("Cleared %d resolved alerts", cleared)
+	return *m, nil
+}
+
+func handleSwitchEvents(m *Model) (Model, tea.Cmd) {
+	if m.NavMode == NavEvents {
+		return *m, nil
+	}
+	m.NavMode = NavEvents
+	m.ViewMode = ViewDetails
+	stopLogStream(m)
+	stopStatsStream(m)
+	// The timeline opens on the newest event
+	m.Cursor = len(m.EventLog)
+	m.Services.RebuildEventItems(m)
+	if m.EventStream == nil {
+		m.StatusMessage = "Docker events disconnected; the timeline resumes on reconnect"
+	}
+	return *m, nil
+}
+
+// Events timeline handlers
+
+// inEventsView runs handler in the events timeline and fallback elsewhere.
+func inEventsView(handler, fallback KeyHandler) KeyHandler {
+	return func(m *Model) (Model, tea.Cmd) {
+		if m.NavMode == NavEvents && m.ViewMode == ViewDetails {
+			return handler(m)
+		}
+		if fallback != nil {
+			return fallback(m)
+		}
+		return *m, nil
+	}
+}
+
+// handleEventJump opens the resource the selected event is about.
+func handleEventJump(m *Model) (Model, tea.Cmd) {
+	if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsEvent {
+		return *m, nil
+	}
+	e := *m.Items[m.Cursor].Event
+	id := shortID(e.ActorID)
+
+	// Look the resource up before leaving the timeline, so a stale event
+	// keeps the user where they were.
+	switch e.Type {
+	case "container":
+		for _, c := range m.Containers {
+			if c.ID == id {
+				jumpToContainer(m, c)
+				return *m, nil
+			}
+		}
+	case "image":
+		imageID := shortID(strings.TrimPrefix(e.ActorID, "sha256:"))
+		match := func(img Image) bool { return img.ID == imageID || slices.Contains(img.RepoTags, e.ActorID) }
+		if slices.ContainsFunc(m.Images, match) {
+			handleSwitchImage(m)
+			selectItem(m, func(item ListItem) bool { return item.IsImage && match(*item.Image) })
+			return *m, nil
+		}
+	case "volume":
+		match := func(v Volume) bool { return v.Name == e.ActorID }
+		if slices.ContainsFunc(m.Volumes, match) {
+			handleSwitchVolume(m)
+			selectItem(m, func(item ListItem) bool { return item.IsVolume && match(*item.Volume) })
+			return *m, nil
+		}
+	case "network":
+		match := func(n Network) bool { return n.ID == id }
+		if slices.ContainsFunc(m.Networks, match) {
+			handleSwitchNetwork(m)
+			selectItem(m, func(item ListItem) bool { return item.IsNetwork && match(*item.Network) })
+			return *m, nil
+		}
+	default:
+		m.StatusMessage = fmt.Sprintf("No view for %s events", e.Type)
+		return *m, nil
+	}
+	m.StatusMessage = fmt.Sprintf("%s %s no longer exists", e.Type, e.Name())
+	return *m, nil
+}
+
+// selectItem moves the cursor to the first list item matching match.
+func selectItem(m *Model, match func(ListItem) bool) {
+	if i := slices.IndexFunc(m.Items, match); i >= 0 {
+		m.Cursor = i
+	}
+}
+
+// handleCycleEventType steps the type filter through all, container,
+// image, volume and network.
+func handleCycleEventType(m *Model) (Model, tea.Cmd) {
+	current := ""
+	if len(m.EventFilter.Types) == 1 {
+		current = m.EventFilter.Types[0]
+	}
+	next := eventTypeCycle[(slices.Index(eventTypeCycle, current)+1)%len(eventTypeCycle)]
+	m.EventFilter.Types = nil
+	if next != "" {
+		m.EventFilter.Types = []string{next}
+	}
+	m.Services.RebuildEventItems(m)
+	return *m, nil
+}
+
 // Container action handlers
 
+// handleRestart restarts the marked containers, or the one or the project
+// under the cursor.
 func handleRestart(m *Model) (Model, tea.Cmd) {
-	return *m, RestartContainerFunc(m)
+	if refuseReadOnly(m, "restart") || refuseNonContainers(m, "restart") {
+		return *m, nil
+	}
+	cmd := m.Services.RestartContainer(m)
+	clearMarks(m)
+	return *m, cmd
 }
 
+// handleDelete deletes the marked items of the current list, or the one
+// under the cursor, after asking if the safety policy says so. Protected
+// items are never deleted.
 func handleDelete(m *Model) (Model, tea.Cmd) {
+	if refuseReadOnly(m, "delete") {
+		return *m, nil
+	}
+	targets := m.ActionTargets()
+	if len(targets) == 0 {
+		return *m, nil
+	}
+	var protected []string
+	for _, item := range targets {
+		if IsProtected(m, item) {
+			protected = append(protected, itemName(item))
+		}
+	}
+	if len(protected) > 0 {
+		m.StatusMessage = "Protected, not deleted: " + strings.Join(protected, ", ")
+		return *m, nil
+	}
+	if !confirmsDelete(m) {
+		return *m, deleteTargets(m)
+	}
+	m.Confirm = deleteConfirmation(m, targets)
+	m.Input = InputConfirm
+	return *m, nil
+}
+
+// deleteTargets deletes the action targets of the current list.
+func deleteTargets(m *Model) tea.Cmd {
+	var cmd tea.Cmd
 	switch m.NavMode {
 	case NavContainers:
-		return *m, DeleteContainerFunc(m)
+		cmd = m.Services.DeleteContainer(m)
 	case NavVolumes:
-		return *m, DeleteVolumeFunc(m)
+		cmd = m.Services.DeleteVolume(m)
 	case NavImages:
-		return *m, DeleteImageFunc(m)
+		cmd = m.Services.DeleteImage(m)
+	case NavNetworks:
+		cmd = m.Services.DeleteNetwork(m)
 	}
-	return *m, nil
+	clearMarks(m)
+	return cmd
 }
 
 func handleLogs(m *Model) (Model, tea.Cmd) {
-	return *m, LoadLogsFunc(m)
+	stopLogStream(m)
+	stopStatsStream(m)
+	return *m, m.Services.LoadLogs(m)
 }
 
 func handleExec(m *Model) (Model, tea.Cmd) {
+	if refuseReadOnly(m, "exec") {
+		return *m, nil
+	}
 	if m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
-		return *m, ExecShellFunc(m)
+		return *m, m.Services.ExecShell(m)
 	}
 	return *m, nil
 }
@@ -267,25 +659,36 @@ func handleEnv(m *Model) (Model, tea.Cmd) {
 	return *m, nil
 }
 
+// handleStats opens the live stats view for the selected container. Inside
+// the stats view it reconnects the stream and keeps the history.
 func handleStats(m *Model) (Model, tea.Cmd) {
-	if m.ViewMode == ViewStats {
-		return *m, LoadStatsFunc(m)
-	} else if m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
-		return *m, LoadStatsFunc(m)
+	if m.ViewMode != ViewStats {
+		if m.Cursor >= len(m.Items) || !m.Items[m.Cursor].IsContainer {
+			return *m, nil
+		}
+		stopLogStream(m)
+		m.Stats = nil
+		m.StatsHistory = nil
 	}
-	return *m, nil
+
+	stopStatsStream(m)
+	cmd := m.Services.LoadStats(m)
+	if cmd != nil {
+		m.ViewMode = ViewStats
+	}
+	return *m, cmd
 }
 
 func handleInspect(m *Model) (Model, tea.Cmd) {
-	if m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
-		return *m, LoadInspectFunc(m)
+	if m.SelectedContainer() != nil {
+		return *m, m.Services.LoadInspect(m)
 	}
 	return *m, nil
 }
 
 func handleOpenPort(m *Model) (Model, tea.Cmd) {
-	if m.ViewMode == ViewPorts && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
-		return *m, OpenPortInBrowserFunc(m)
+	if m.ViewMode == ViewPorts && m.SelectedContainer() != nil {
+		return *m, m.Services.OpenPortInBrowser(m)
 	}
 	return *m, nil
 }
@@ -293,7 +696,7 @@ func handleOpenPort(m *Model) (Model, tea.Cmd) {
 // Command and search handlers
 
 func handleCommandMode(m *Model) (Model, tea.Cmd) {
-	m.CommandMode = true
+	m.Input = InputCommand
 	m.CommandInput = ""
 	m.StatusMessage = ""
 	return *m, nil
@@ -301,7 +704,7 @@ func handleCommandMode(m *Model) (Model, tea.Cmd) {
 
 func handleSearch(m *Model) (Model, tea.Cmd) {
 	if m.ViewMode == ViewLogs {
-		m.SearchMode = true
+		m.Input = InputSearch
 		m.SearchQuery = ""
 		m.StatusMessage = ""
 	}
@@ -332,6 +735,43 @@ func handlePrevSearchResult(m *Model) (Model, tea.Cmd) {
 	return *m, nil
 }
 
+func handleToggleSmartCase(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	m.SearchSmartCase = !m.SearchSmartCase
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	if m.SearchSmartCase {
+		m.StatusMessage = "Smart case on"
+	} else {
+		m.StatusMessage = "Smart case off: search ignores case"
+	}
+	return *m, nil
+}
+
+// handleToggleSearchFilter switches between highlighting matches and showing
+// only matching lines with SearchContext lines around them.
+func handleToggleSearchFilter(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	m.SearchFilter = !m.SearchFilter
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	if m.SearchFilter {
+		m.StatusMessage = fmt.Sprintf("Filter mode on: matches with %d context lines", m.SearchContext)
+	} else {
+		m.StatusMessage = "Filter mode off"
+	}
+	return *m, nil
+}
+
 func handleToggleLogFollow(m *Model) (Model, tea.Cmd) {
 	if m.ViewMode != ViewLogs {
 		return *m, nil
@@ -340,29 +780,147 @@ func handleToggleLogFollow(m *Model) (Model, tea.Cmd) {
 	m.FollowingLogs = !m.FollowingLogs
 	if m.FollowingLogs {
 		m.StatusMessage = "Log follow enabled"
-		return *m, FollowLogsFunc(m)
+		cmd := m.Services.FollowLogs(m)
+		return *m, cmd
 	}
 
+	stopLogStream(m)
 	m.StatusMessage = "Log follow paused"
 	return *m, nil
 }
 
+func handleCycleLogStreams(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	m.LogStreamFilter = (m.LogStreamFilter + 1) % 3
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	m.StatusMessage = "Showing streams: " + m.LogStreamFilter.String()
+	return *m, nil
+}
+
+func handleLoadOlderLogs(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs || m.LoadingOlder || m.LogsAtStart || len(m.LogTargets) == 0 {
+		return *m, nil
+	}
+
+	m.LoadingOlder = true
+	m.StatusMessage = "Loading older lines..."
+	return *m, m.Services.LoadOlderLogs(m)
+}
+
+func handleToggleJSONView(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	m.LogJSONView = !m.LogJSONView
+	if m.LogJSONView {
+		m.StatusMessage = "JSON columns on"
+	} else {
+		m.StatusMessage = "JSON columns off"
+	}
+	return *m, nil
+}
+
+func handleToggleLogDetail(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	m.LogDetail = !m.LogDetail
+	return *m, nil
+}
+
+// handleCycleMinLevel steps the minimum level through all, info, warn and error.
+func handleCycleMinLevel(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs {
+		return *m, nil
+	}
+
+	switch m.LogMinLevel {
+	case LevelUnknown, LevelDebug:
+		m.LogMinLevel = LevelInfo
+	case LevelInfo:
+		m.LogMinLevel = LevelWarn
+	case LevelWarn:
+		m.LogMinLevel = LevelError
+	default:
+		m.LogMinLevel = LevelUnknown
+	}
+
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	m.StatusMessage = "Minimum level: " + m.LogMinLevel.String()
+	return *m, nil
+}
+
+// handleCycleLogSource steps through the services of a project log view,
+// showing one at a time, and returns to showing all after the last one.
+func handleCycleLogSource(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewLogs || m.LogProject == "" || len(m.LogTargets) == 0 {
+		return *m, nil
+	}
+
+	// Find the service currently shown on its own, if any
+	solo := -1
+	visible := 0
+	for i, t := range m.LogTargets {
+		if !m.HiddenSources[t.Source] {
+			solo = i
+			visible++
+		}
+	}
+	if visible != 1 {
+		solo = -1
+	}
+
+	next := solo + 1
+	if next >= len(m.LogTargets) {
+		m.HiddenSources = nil
+		m.StatusMessage = "Showing all services"
+	} else {
+		m.HiddenSources = make(map[string]bool)
+		for i, t := range m.LogTargets {
+			if i != next {
+				m.HiddenSources[t.Source] = true
+			}
+		}
+		m.StatusMessage = "Showing only " + m.LogTargets[next].Source
+	}
+
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	return *m, nil
+}
+
 // View handlers
 
 func handleBack(m *Model) (Model, tea.Cmd) {
-	if m.HelpMode {
-		m.HelpMode = false
-		m.StatusMessage = ""
-	} else if m.ViewMode == ViewLogs || m.ViewMode == ViewPorts || m.ViewMode == ViewEnv || m.ViewMode == ViewStats || m.ViewMode == ViewInspect {
+	// Operations in flight are cancelled first; the next esc goes back.
+	if n := m.CancelOperations(); n > 0 {
+		m.StatusMessage = cancelledStatus(n)
+		return *m, nil
+	}
+	// Marks are cleared next, before esc leaves anything
+	if m.ViewMode == ViewDetails && len(m.MarkedItems()) > 0 {
+		clearMarks(m)
+		m.StatusMessage = "Marks cleared"
+		return *m, nil
+	}
+	if m.ViewMode == ViewLogs || m.ViewMode == ViewPorts || m.ViewMode == ViewEnv || m.ViewMode == ViewStats || m.ViewMode == ViewInspect {
 		m.ViewMode = ViewDetails
-		m.Logs = nil
-		m.LogSince = time.Time{}
-		m.FollowingLogs = false
+		m.LogsState.reset()
+		m.StatsState.reset()
 		m.SelectedPort = 0
-		m.SearchQuery = ""
-		m.SearchResults = nil
-		m.SearchResultIdx = 0
-		m.Stats = nil
 		m.InspectData = ""
 		m.StatusMessage = ""
 	}
@@ -372,55 +930,384 @@ func handleBack(m *Model) (Model, tea.Cmd) {
 // General handlers
 
 func handleForceQuit(m *Model) (Model, tea.Cmd) {
-	QuitFunc(m)
+	stopLogStream(m)
+	stopStatsStream(m)
+	stopEventStream(m)
+	m.Services.Quit(m)
 	return *m, tea.Quit
 }
 
 // Command handlers
 
-// CommandHandler is a function that executes a command
-type CommandHandler func(*Model) tea.Cmd
+// CommandHandler is a function that executes a command with its arguments
+type CommandHandler func(*Model, []string) tea.Cmd
 
 // buildCommandHandlerMap creates a map of command -> handler function
 func buildCommandHandlerMap() map[string]CommandHandler {
 	return map[string]CommandHandler{
-		"q":     cmdQuit,
-		"quit":  cmdQuit,
-		"s":     cmdStart,
-		"start": cmdStart,
-		"S":     cmdStop,
-		"stop":  cmdStop,
-		"noh":   cmdNoHighlight,
-		"help":  cmdHelp,
-		"h":     cmdHelp,
+		"q":       cmdQuit,
+		"quit":    cmdQuit,
+		"s":       cmdStart,
+		"start":   cmdStart,
+		"S":       cmdStop,
+		"stop":    cmdStop,
+		"noh":     cmdNoHighlight,
+		"help":    cmdHelp,
+		"h":       cmdHelp,
+		"svc":     cmdToggleSource,
+		"logs":    cmdLogs,
+		"filter":  cmdFilter,
+		"ctx":     cmdSearchContext,
+		"w":       cmdWriteLogs,
+		"w!":      cmdExportLogs,
+		"sort":    cmdSort,
+		"alerts":  cmdAlerts,
+		"events":  cmdEvents,
+		"cancel":  cmdCancel,
+		"context": cmdContext,
 	}
 }
 
-func cmdQuit(m *Model) tea.Cmd {
-	QuitFunc(m)
+func cmdQuit(m *Model, _ []string) tea.Cmd {
+	stopLogStream(m)
+	stopStatsStream(m)
+	stopEventStream(m)
+	m.Services.Quit(m)
 	return tea.Quit
 }
 
-func cmdStart(m *Model) tea.Cmd {
+func cmdStart(m *Model, _ []string) tea.Cmd {
+	if refuseReadOnly(m, "start") || refuseNonContainers(m, "start") {
+		return nil
+	}
 	m.StatusMessage = "Starting container..."
-	return StartContainerFunc(m)
+	cmd := m.Services.StartContainer(m)
+	clearMarks(m)
+	return cmd
 }
 
-func cmdStop(m *Model) tea.Cmd {
+func cmdStop(m *Model, _ []string) tea.Cmd {
+	if refuseReadOnly(m, "stop") || refuseNonContainers(m, "stop") {
+		return nil
+	}
 	m.StatusMessage = "Stopping container..."
-	return StopContainerFunc(m)
+	cmd := m.Services.StopContainer(m)
+	clearMarks(m)
+	return cmd
+}
+
+// refuseNonContainers refuses a container action whose targets include
+// other items, such as volumes marked in the volume list, and names them.
+func refuseNonContainers(m *Model, action string) bool {
+	var others []string
+	for _, item := range m.ActionTargets() {
+		if !item.IsContainer {
+			others = append(others, itemName(item))
+		}
+	}
+	if len(others) == 0 {
+		return false
+	}
+	m.StatusMessage = fmt.Sprintf("Cannot %s %s: %s only applies to containers", action, strings.Join(others, ", "), action)
+	return true
 }
 
-func cmdNoHighlight(m *Model) tea.Cmd {
+func cmdNoHighlight(m *Model, _ []string) tea.Cmd {
 	m.SearchQuery = ""
 	m.SearchResults = nil
 	m.SearchResultIdx = 0
+	clearSearchRegex(m)
 	m.StatusMessage = "Search cleared"
 	return nil
 }
 
-func cmdHelp(m *Model) tea.Cmd {
-	m.HelpMode = true
+// parseWriteArgs reads "[-T] <path>" for :w and :w!, given as the rest of
+// the command line. -T leaves out the Docker timestamps; everything after
+// it is the path, spaces included.
+func parseWriteArgs(args []string) (path string, timestamps bool, ok bool) {
+	if len(args) == 0 {
+		return "", false, false
+	}
+	path, timestamps = args[0], true
+	for _, flag := range []string{"-T", "--no-timestamps"} {
+		if rest, found := strings.CutPrefix(path, flag); found && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			path, timestamps = strings.TrimSpace(rest), false
+			break
+		}
+	}
+	if path == "" {
+		return "", false, false
+	}
+	return path, timestamps, true
+}
+
+// cmdWriteLogs writes the lines shown in the log view to a file.
+func cmdWriteLogs(m *Model, args []string) tea.Cmd {
+	if m.ViewMode != ViewLogs {
+		m.StatusMessage = ":w saves the logs view"
+		return nil
+	}
+	path, timestamps, ok := parseWriteArgs(args)
+	if !ok {
+		m.StatusMessage = "Usage: :w [-T] <path>"
+		return nil
+	}
+
+	m.StatusMessage = "Saving logs..."
+	return m.Services.SaveLogs(m, path, timestamps)
+}
+
+// cmdExportLogs streams the full log history of the log view's containers
+// to a file in the background.
+func cmdExportLogs(m *Model, args []string) tea.Cmd {
+	if m.ViewMode != ViewLogs || len(m.LogTargets) == 0 {
+		m.StatusMessage = ":w! saves the logs view"
+		return nil
+	}
+	if m.LogExport != nil {
+		m.StatusMessage = "A log export to " + m.LogExport.Path + " is already running"
+		return nil
+	}
+	path, timestamps, ok := parseWriteArgs(args)
+	if !ok {
+		m.StatusMessage = "Usage: :w! [-T] <path>"
+		return nil
+	}
+
+	m.StatusMessage = "Saving full log to " + path + "..."
+	return m.Services.ExportLogs(m, path, timestamps)
+}
+
+// cmdSort sorts the overview by a column, e.g. ":sort mem". Names sort
+// ascending and numbers descending unless "asc" or "desc" is given.
+func cmdSort(m *Model, args []string) tea.Cmd {
+	if m.NavMode != NavTop {
+		m.StatusMessage = ":sort applies to the overview (5)"
+		return nil
+	}
+	if len(args) == 0 || len(args) > 2 {
+		m.StatusMessage = "Usage: :sort <" + strings.Join(topColumnNames, "|") + "> [asc|desc]"
+		return nil
+	}
+
+	column, ok := ParseTopColumn(args[0])
+	if !ok {
+		m.StatusMessage = fmt.Sprintf("Unknown column: %s", args[0])
+		return nil
+	}
+	asc := column == TopColumnName
+	if len(args) == 2 {
+		switch args[1] {
+		case "asc":
+			asc = true
+		case "desc":
+			asc = false
+		default:
+			m.StatusMessage = "Sort order must be asc or desc"
+			return nil
+		}
+	}
+
+	setTopSort(m, column, asc)
+	return nil
+}
+
+// cmdAlerts opens the alerts view; ":alerts clear" drops resolved alerts.
+func cmdAlerts(m *Model, args []string) tea.Cmd {
+	switch {
+	case len(args) == 0:
+		handleSwitchAlerts(m)
+	case len(args) == 1 && args[0] == "clear":
+		handleClearAlerts(m)
+	default:
+		m.StatusMessage = "Usage: :alerts [clear]"
+	}
+	return nil
+}
+
+// cmdEvents opens the events timeline and sets its filters, e.g.
+// ":events type container", ":events action die,oom", ":events container api*",
+// ":events project shop" or ":events since 1h". ":events clear" resets them.
+func cmdEvents(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		handleSwitchEvents(m)
+		return nil
+	}
+
+	usage := "Usage: :events [type|action <a,b> | container|project <name> | since <time> | clear]"
+	var cmd tea.Cmd
+	switch {
+	case args[0] == "clear" && len(args) == 1:
+		m.EventFilter = EventFilter{}
+	case args[0] == "type" && len(args) == 2:
+		m.EventFilter.Types = strings.Split(args[1], ",")
+	case args[0] == "action" && len(args) == 2:
+		m.EventFilter.Actions = strings.Split(args[1], ",")
+	case args[0] == "container" && len(args) == 2:
+		m.EventFilter.Container = args[1]
+	case args[0] == "project" && len(args) == 2:
+		m.EventFilter.Project = args[1]
+	case args[0] == "since" && len(args) == 2:
+		if !validLogTime(args[1]) {
+			m.StatusMessage = fmt.Sprintf("Invalid time %q: use a duration like 1h or an RFC3339 timestamp", args[1])
+			return nil
+		}
+		m.EventLoading = true
+		m.StatusMessage = "Loading events since " + args[1] + "..."
+		cmd = m.Services.LoadEventHistory(m, args[1])
+	default:
+		m.StatusMessage = usage
+		return nil
+	}
+
+	if m.NavMode == NavEvents {
+		m.Services.RebuildEventItems(m)
+	} else {
+		handleSwitchEvents(m)
+	}
+	return cmd
+}
+
+// cmdSearchContext sets how many lines around each match filter mode keeps.
+func cmdSearchContext(m *Model, args []string) tea.Cmd {
+	n := -1
+	if len(args) == 1 {
+		n, _ = strconv.Atoi(args[0])
+	}
+	if n < 0 {
+		m.StatusMessage = "Usage: :ctx <lines>"
+		return nil
+	}
+
+	m.SearchContext = n
+	if m.SearchFilter {
+		rebuildVisibleLogs(m)
+		if m.SearchQuery != "" {
+			performSearch(m)
+		}
+	}
+	m.StatusMessage = fmt.Sprintf("Search context: %d lines", n)
+	return nil
+}
+
+func cmdHelp(m *Model, _ []string) tea.Cmd {
+	m.Input = InputHelp
+	m.HelpScroll = 0
 	m.StatusMessage = ""
 	return nil
 }
+
+// cmdToggleSource hides or shows a service in the project log view.
+// Without arguments every service is shown again.
+func cmdToggleSource(m *Model, args []string) tea.Cmd {
+	if m.ViewMode != ViewLogs || m.LogProject == "" {
+		m.StatusMessage = "Service filters apply to project logs only"
+		return nil
+	}
+
+	if len(args) == 0 {
+		m.HiddenSources = nil
+		m.StatusMessage = "Showing all services"
+	} else {
+		source := args[0]
+		known := false
+		for _, t := range m.LogTargets {
+			if t.Source == source {
+				known = true
+				break
+			}
+		}
+		if !known {
+			m.StatusMessage = fmt.Sprintf("Unknown service: %s", source)
+			return nil
+		}
+		if m.HiddenSources == nil {
+			m.HiddenSources = make(map[string]bool)
+		}
+		m.HiddenSources[source] = !m.HiddenSources[source]
+		if m.HiddenSources[source] {
+			m.StatusMessage = "Hiding " + source
+		} else {
+			m.StatusMessage = "Showing " + source
+		}
+	}
+
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	return nil
+}
+
+// cmdLogs (re)opens the log view for the selection with a time range:
+// ":logs since 10m", ":logs until 2024-01-02T15:04:05Z" or ":logs all".
+func cmdLogs(m *Model, args []string) tea.Cmd {
+	switch {
+	case len(args) == 0:
+		// Reopen with the current range
+	case args[0] == "all" && len(args) == 1:
+		m.LogRangeSince = ""
+		m.LogRangeUntil = ""
+	case (args[0] == "since" || args[0] == "until") && len(args) == 2:
+		if !validLogTime(args[1]) {
+			m.StatusMessage = fmt.Sprintf("Invalid time %q: use a duration like 10m or an RFC3339 timestamp", args[1])
+			return nil
+		}
+		if args[0] == "since" {
+			m.LogRangeSince = args[1]
+		} else {
+			m.LogRangeUntil = args[1]
+		}
+	default:
+		m.StatusMessage = "Usage: :logs [since <time> | until <time> | all]"
+		return nil
+	}
+
+	stopLogStream(m)
+	cmd := m.Services.LoadLogs(m)
+	if cmd == nil {
+		m.StatusMessage = "Select a container or project to view logs"
+		return nil
+	}
+	m.StatusMessage = "Loading logs..."
+	return cmd
+}
+
+// validLogTime accepts the time formats Docker understands for since/until
+// that users are likely to type: relative durations and RFC3339 timestamps.
+func validLogTime(value string) bool {
+	if _, err := time.ParseDuration(value); err == nil {
+		return true
+	}
+	if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return true
+	}
+	return false
+}
+
+// cmdFilter hides log lines whose JSON fields do not match every term, e.g.
+// ":filter level=error service=api". Without arguments the filter is cleared.
+func cmdFilter(m *Model, args []string) tea.Cmd {
+	if m.ViewMode != ViewLogs {
+		m.StatusMessage = "Filters apply to the logs view only"
+		return nil
+	}
+
+	filters, err := parseFieldFilters(args)
+	if err != nil {
+		m.StatusMessage = err.Error()
+		return nil
+	}
+	m.LogFieldFilters = filters
+
+	rebuildVisibleLogs(m)
+	if m.SearchQuery != "" {
+		performSearch(m)
+	}
+	if len(filters) == 0 {
+		m.StatusMessage = "Filter cleared"
+	} else {
+		m.StatusMessage = fmt.Sprintf("Filter: %d of %d lines match", len(m.VisibleLogs), len(m.Logs))
+	}
+	return nil
+}
@@ -0,0 +1,855 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gdocker/config"
+	"gdocker/docker"
+	"gdocker/docker/dockertest"
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// newModel loads the fake daemon's resources into a model with the default
+// key bindings, as InitialModel does for a real one.
+func newModel(t *testing.T, fake *dockertest.Fake) models.Model {
+	t.Helper()
+	m := models.NewModel(fake, docker.Services{}, nil)
+	m.KeyBindings = config.Default()
+	m.Width, m.Height = 120, 40
+	return load(t, m, docker.RefreshContainers, docker.ReloadVolumes, docker.ReloadImages, docker.ReloadNetworks)
+}
+
+// load runs the given list loaders and applies their results, as the
+// Bubble Tea runtime does.
+func load(t *testing.T, m models.Model, loaders ...func(*models.Model) tea.Cmd) models.Model {
+	t.Helper()
+	for _, loader := range loaders {
+		msg := loader(&m)()
+		if failed, ok := msg.(models.ResourceFailedMsg); ok {
+			t.Fatalf("loading %s: %v", failed.Resource, failed.Err)
+		}
+		m, _ = update(m, msg)
+	}
+	return m
+}
+
+// press sends each key to the model and returns the command of the last one.
+func press(t *testing.T, m models.Model, keys ...string) (models.Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(models.Model)
+	}
+	return m, cmd
+}
+
+// command types a command-mode command and runs it.
+func command(t *testing.T, m models.Model, text string) (models.Model, tea.Cmd) {
+	t.Helper()
+	keys := []string{":"}
+	for _, r := range text {
+		keys = append(keys, string(r))
+	}
+	return press(t, m, append(keys, "enter")...)
+}
+
+// update feeds msg to the model.
+func update(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	next, cmd := m.Update(msg)
+	return next.(models.Model), cmd
+}
+
+var errAction = errors.New("volume is in use")
+
+func composeFake() *dockertest.Fake {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "nginx", Image: "nginx", ImageID: "0123456789abcdef0123456789abcdef"})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "shop-api-1", Project: "shop", Service: "api", Networks: []string{"backend"}})
+	fake.AddContainer(dockertest.Container{ID: "cccccccccccc", Name: "shop-db-1", Project: "shop", Service: "db", State: "exited", Volumes: []string{"data"}, Networks: []string{"backend"}})
+	fake.AddVolume("data", "local")
+	fake.AddImage("0123456789abcdef0123456789abcdef", 1024, "nginx:latest")
+	fake.AddNetwork("fedcba9876543210", "backend", "bridge")
+	return fake
+}
+
+func TestSwitchViews(t *testing.T) {
+	m := newModel(t, composeFake())
+
+	tests := []struct {
+		key   string
+		mode  models.NavigationMode
+		check func(models.ListItem) bool
+	}{
+		{"2", models.NavVolumes, func(i models.ListItem) bool { return i.IsVolume && i.Volume.Name == "data" }},
+		{"3", models.NavImages, func(i models.ListItem) bool { return i.IsImage && i.Image.ID == "0123456789ab" }},
+		{"4", models.NavNetworks, func(i models.ListItem) bool { return i.IsNetwork && i.Network.Name == "backend" }},
+		{"1", models.NavContainers, func(i models.ListItem) bool { return i.IsContainer && i.Container.Name == "nginx" }},
+	}
+	for _, tt := range tests {
+		m, _ = press(t, m, tt.key)
+		if m.NavMode != tt.mode {
+			t.Errorf("key %s: NavMode = %v, want %v", tt.key, m.NavMode, tt.mode)
+			continue
+		}
+		if len(m.Items) == 0 || !tt.check(m.Items[0]) {
+			t.Errorf("key %s: first item = %+v", tt.key, m.Items)
+		}
+	}
+}
+
+func TestExpandProject(t *testing.T) {
+	m := newModel(t, composeFake())
+
+	m, _ = press(t, m, "j")
+	if !m.Items[m.Cursor].IsProject {
+		t.Fatalf("cursor on %+v, want the shop project", m.Items[m.Cursor])
+	}
+	m, _ = press(t, m, " ")
+	if len(m.Items) != 4 {
+		t.Fatalf("expanded list has %d items, want 4", len(m.Items))
+	}
+	m, _ = press(t, m, "j", "j")
+	if item := m.Items[m.Cursor]; !item.IsContainer || item.Container.Name != "shop-db-1" {
+		t.Errorf("cursor on %+v, want shop-db-1", item)
+	}
+}
+
+func TestStartCommandCallsDocker(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m, _ = press(t, m, "j", " ", "j", "j") // shop-db-1, exited
+
+	m, cmd := command(t, m, "start")
+	if cmd == nil {
+		t.Fatal(":start returned no command")
+	}
+	if len(m.Operations) != 1 || m.Operations[0].Label != "Starting shop-db-1" {
+		t.Fatalf("operations = %+v, want the start in flight", m.Operations)
+	}
+	done := waitFor[models.OperationDoneMsg](t, cmd)
+	msg, ok := done.Msg.(models.ActionResultMsg)
+	if !ok || !msg.Success {
+		t.Fatalf(":start = %+v", done.Msg)
+	}
+	if calls := fake.Calls("ContainerStart"); len(calls) != 1 || calls[0].ID != "cccccccccccc" {
+		t.Errorf("ContainerStart calls = %+v", calls)
+	}
+
+	// Without an event stream the list is refreshed after the action
+	m, cmd = update(m, done)
+	if len(m.Operations) != 0 {
+		t.Errorf("operations = %+v after the start returned", m.Operations)
+	}
+	if m.StatusMessage != "Container started" || cmd == nil {
+		t.Fatalf("status %q, refresh cmd %v", m.StatusMessage, cmd != nil)
+	}
+	refreshed, ok := cmd().(models.ContainersRefreshedMsg)
+	if !ok {
+		t.Fatalf("refresh returned %T", refreshed)
+	}
+	m, _ = update(m, refreshed)
+	for _, c := range m.Containers {
+		if c.Name == "shop-db-1" && c.State != "running" {
+			t.Errorf("shop-db-1 state = %s after start", c.State)
+		}
+	}
+}
+
+func TestDeleteFailureShowsError(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m, _ = press(t, m, "2")
+	fake.Fail("VolumeRemove", errAction)
+
+	m, cmd := press(t, m, "d", "y")
+	if cmd == nil {
+		t.Fatal("delete returned no command")
+	}
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	if m.StatusMessage != "Failed to delete: "+errAction.Error() {
+		t.Errorf("status = %q", m.StatusMessage)
+	}
+	if len(m.Volumes) != 1 {
+		t.Errorf("volumes = %+v, want data kept", m.Volumes)
+	}
+}
+
+func TestDeleteAsksFirst(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m, _ = press(t, m, "2")
+
+	m, cmd := press(t, m, "d")
+	if cmd != nil || m.Input != models.InputConfirm || m.Confirm == nil {
+		t.Fatalf("delete did not ask first: input %v, confirm %+v", m.Input, m.Confirm)
+	}
+	if m.Confirm.Title != "Delete volume data?" {
+		t.Errorf("title = %q", m.Confirm.Title)
+	}
+	if want := []string{"data is used by shop-db-1 (exited)"}; !slices.Equal(m.Confirm.Dependents, want) {
+		t.Errorf("dependents = %q, want %q", m.Confirm.Dependents, want)
+	}
+
+	// Other keys do nothing while the dialog is open
+	m, _ = press(t, m, "j", "d", "n")
+	if m.Input != models.InputNormal || m.Confirm != nil || len(fake.Calls("VolumeRemove")) != 0 {
+		t.Fatalf("n did not cancel: input %v, calls %+v", m.Input, fake.Calls("VolumeRemove"))
+	}
+
+	_, cmd = press(t, m, "d", "y")
+	waitFor[models.OperationDoneMsg](t, cmd)
+	if calls := fake.Calls("VolumeRemove"); len(calls) != 1 || calls[0].ID != "data" {
+		t.Errorf("VolumeRemove calls = %+v, want data", calls)
+	}
+}
+
+func TestDeletePolicy(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m.Safety = &config.SafetyConfig{ConfirmDelete: config.ConfirmOnlyForVolumes, Protected: []string{"shop-*", "nginx:*"}}
+
+	// Protected items are never deleted, marked together with others or not
+	m, _ = press(t, m, "M", "d")
+	if m.Input != models.InputNormal || m.StatusMessage != "Protected, not deleted: shop-api-1, shop-db-1" {
+		t.Errorf("input %v, status %q, want the protected containers refused", m.Input, m.StatusMessage)
+	}
+	m, _ = press(t, m, "3", "d")
+	if m.StatusMessage != "Protected, not deleted: nginx:latest" {
+		t.Errorf("status = %q, want the protected image refused", m.StatusMessage)
+	}
+	if len(fake.Calls("ContainerRemove"))+len(fake.Calls("ImageRemove")) != 0 {
+		t.Error("a protected item was removed")
+	}
+
+	// Only volumes ask first
+	m, cmd := press(t, m, "4", "d")
+	if m.Input != models.InputNormal || cmd == nil {
+		t.Fatalf("network delete: input %v, want no dialog", m.Input)
+	}
+	waitFor[models.OperationDoneMsg](t, cmd)
+	if calls := fake.Calls("NetworkRemove"); len(calls) != 1 {
+		t.Errorf("NetworkRemove calls = %+v, want 1", calls)
+	}
+	m, _ = press(t, m, "2", "d")
+	if m.Input != models.InputConfirm {
+		t.Error("volume delete did not ask first")
+	}
+}
+
+func TestReadOnlyRefusesActions(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m.ReadOnly = true
+
+	for _, tc := range []struct {
+		keys   []string
+		action string
+	}{
+		{[]string{"r"}, "restart"},
+		{[]string{"d"}, "delete"},
+		{[]string{"e"}, "exec"},
+		{[]string{":", "s", "enter"}, "start"},
+		{[]string{":", "S", "enter"}, "stop"},
+	} {
+		next, cmd := press(t, m, tc.keys...)
+		if want := "Read-only mode: " + tc.action + " is disabled"; cmd != nil || next.StatusMessage != want || next.Input != models.InputNormal {
+			t.Errorf("%v: status %q, cmd %v, want %q and nothing run", tc.keys, next.StatusMessage, cmd != nil, want)
+		}
+	}
+
+	// The backend refuses as well, whatever the handlers do
+	services := models.ReadOnlyServices{Services: docker.Services{}}
+	if msg := services.StopContainer(&m)().(models.ActionResultMsg); msg.Success || msg.Message != "Read-only mode: stop is disabled" {
+		t.Errorf("StopContainer = %+v", msg)
+	}
+	if calls := fake.Calls("ContainerStop"); len(calls) != 0 {
+		t.Errorf("ContainerStop calls = %+v, want none", calls)
+	}
+}
+
+func TestEscCancelsOperations(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m, _ = press(t, m, "j", " ", "j", "j", "v") // shop-db-1's environment
+	fake.Hang("ContainerStart", true)
+
+	m, cmd := command(t, m, "start")
+	m, _ = press(t, m, "i") // Other keys work while the start hangs
+	if len(m.Operations) != 2 || m.Operations[1].Label != "Inspecting shop-db-1" {
+		t.Fatalf("operations = %+v, want the start and the inspect", m.Operations)
+	}
+
+	// The first esc cancels, the second goes back
+	m, _ = press(t, m, "esc")
+	if len(m.Operations) != 0 || m.StatusMessage != "Cancelled 2 operations" || m.ViewMode != models.ViewEnv {
+		t.Fatalf("after esc: %d operations, status %q, view %v", len(m.Operations), m.StatusMessage, m.ViewMode)
+	}
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	if m.StatusMessage != "Failed to start: cancelled" {
+		t.Errorf("status = %q", m.StatusMessage)
+	}
+	m, _ = press(t, m, "esc")
+	if m.ViewMode != models.ViewDetails {
+		t.Errorf("view = %v after the second esc", m.ViewMode)
+	}
+
+	m, _ = command(t, m, "cancel")
+	if m.StatusMessage != "Nothing to cancel" {
+		t.Errorf(":cancel status = %q", m.StatusMessage)
+	}
+}
+
+func TestBulkStopMarkedContainers(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+
+	// Marking the collapsed project row marks both of its containers
+	m, _ = press(t, m, "m", "m")
+	if got := len(m.MarkedItems()); got != 3 {
+		t.Fatalf("%d marked, want nginx and the shop containers", got)
+	}
+	m, _ = press(t, m, "*")
+	if got := len(m.MarkedItems()); got != 0 {
+		t.Fatalf("%d marked after invert, want none", got)
+	}
+	m, _ = press(t, m, "M")
+
+	// nginx goes away before the stop reaches it
+	if _, err := fake.ContainerRemove(context.Background(), "aaaaaaaaaaaa", client.ContainerRemoveOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	m, cmd := command(t, m, "stop")
+	if len(m.MarkedItems()) != 0 || len(m.Operations) != 1 || m.Operations[0].Label != "Stopping 3 containers" {
+		t.Fatalf("marks %d, operations %+v", len(m.MarkedItems()), m.Operations)
+	}
+	m, cmd = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	want := "Stopped 2 of 3 containers: shop-api-1, shop-db-1; failed: nginx (Error response from daemon: No such container: aaaaaaaaaaaa)"
+	if m.StatusMessage != want {
+		t.Errorf("status = %q\nwant %q", m.StatusMessage, want)
+	}
+	if calls := fake.Calls("ContainerStop"); len(calls) != 3 {
+		t.Errorf("ContainerStop calls = %+v", calls)
+	}
+	// Without an event stream the list is reloaded
+	waitFor[models.ContainersRefreshedMsg](t, cmd)
+}
+
+func TestContainerActionsRefuseOtherItems(t *testing.T) {
+	fake := composeFake()
+	fake.AddVolume("cache", "local")
+	m := newModel(t, fake)
+	m, _ = press(t, m, "2", "M")
+
+	for _, action := range []string{"start", "stop"} {
+		m, cmd := command(t, m, action)
+		want := "Cannot " + action + " data, cache: " + action + " only applies to containers"
+		if cmd != nil || m.StatusMessage != want {
+			t.Errorf(":%s status = %q, want %q", action, m.StatusMessage, want)
+		}
+		if got := len(m.MarkedItems()); got != 2 {
+			t.Errorf(":%s left %d marked, want the marks kept", action, got)
+		}
+	}
+	if calls := append(fake.Calls("ContainerStart"), fake.Calls("ContainerStop")...); len(calls) != 0 {
+		t.Errorf("containers were touched: %+v", calls)
+	}
+}
+
+func TestProjectRowTargetsItsContainers(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m, _ = press(t, m, "j") // the shop project
+
+	m, cmd := press(t, m, "r")
+	msg, ok := waitFor[models.OperationDoneMsg](t, cmd).Msg.(models.BulkResultMsg)
+	if !ok || msg.Action != "Restarted" || len(msg.Results) != 2 {
+		t.Fatalf("restart = %+v", msg)
+	}
+	var ids []string
+	for _, call := range fake.Calls("ContainerRestart") {
+		ids = append(ids, call.ID)
+	}
+	slices.Sort(ids) // The restarts run concurrently
+	if !slices.Equal(ids, []string{"bbbbbbbbbbbb", "cccccccccccc"}) {
+		t.Errorf("restarted %v, want both shop containers", ids)
+	}
+}
+
+func TestEventUpdatesContainer(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m.EventStream = nil
+	m, cmd := update(m, models.EventsReconnectMsg{})
+	if m.EventStream == nil || cmd == nil {
+		t.Fatal("reconnect did not subscribe to events")
+	}
+	defer m.EventStream.Cancel()
+
+	// A container stopped outside gdocker
+	fake.SetState("aaaaaaaaaaaa", "exited")
+	fake.Emit(events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor:  events.Actor{ID: "aaaaaaaaaaaa", Attributes: map[string]string{"name": "nginx"}},
+	})
+
+	msg := waitFor[models.DockerEventsMsg](t, m.EventStream.Next())
+	m, cmd = update(m, msg)
+	if len(m.EventLog) != 1 || m.EventLog[0].Action != "die" {
+		t.Errorf("event log = %+v", m.EventLog)
+	}
+	updated := waitFor[models.ContainerUpdatedMsg](t, cmd)
+	m, _ = update(m, updated)
+	if m.Containers[0].State != "exited" {
+		t.Errorf("nginx state = %s, want exited", m.Containers[0].State)
+	}
+}
+
+func TestEventStreamResumesAfterReconnect(t *testing.T) {
+	fake := composeFake()
+	m := newModel(t, fake)
+	m.EventStream = nil
+	m, _ = update(m, models.EventsReconnectMsg{})
+	nginx := events.Actor{ID: "aaaaaaaaaaaa", Attributes: map[string]string{"name": "nginx"}}
+	fake.Emit(events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: nginx})
+	m, _ = update(m, waitFor[models.DockerEventsMsg](t, m.EventStream.Next()))
+
+	// The stream drops and nginx is started before it is back
+	m.EventStream.Cancel()
+	m, _ = update(m, models.EventStreamEndedMsg{Stream: m.EventStream})
+	fake.Emit(events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: nginx})
+
+	m, _ = update(m, models.EventsReconnectMsg{})
+	if m.EventStream == nil {
+		t.Fatal("reconnect did not subscribe to events")
+	}
+	defer m.EventStream.Cancel()
+	m, _ = update(m, waitFor[models.DockerEventsMsg](t, m.EventStream.Next()))
+	var actions []string
+	for _, event := range m.EventLog {
+		actions = append(actions, event.Action)
+	}
+	if !slices.Equal(actions, []string{"die", "start"}) {
+		t.Errorf("timeline = %v, want the die and the start missed while down", actions)
+	}
+}
+
+// waitFor runs cmd, unpacking batches, until it yields a T. Commands that
+// do not finish within a second, such as ticks, are skipped.
+func waitFor[T tea.Msg](t *testing.T, cmd tea.Cmd) T {
+	t.Helper()
+	queue := []tea.Cmd{cmd}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == nil {
+			continue
+		}
+		done := make(chan tea.Msg, 1)
+		go func() { done <- next() }()
+		select {
+		case msg := <-done:
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				queue = append(queue, batch...)
+				continue
+			}
+			if want, ok := msg.(T); ok {
+				return want
+			}
+		case <-time.After(time.Second):
+		}
+	}
+	var zero T
+	t.Fatalf("no %T produced", zero)
+	return zero
+}
+
+func TestEventsCommandFilters(t *testing.T) {
+	m := newModel(t, composeFake())
+	m, _ = update(m, models.DockerEventsMsg{Events: []models.DockerEvent{
+		{Time: time.Now(), Type: "container", Action: "start", ActorID: "aaaaaaaaaaaa", Attributes: map[string]string{"name": "nginx"}},
+		{Time: time.Now(), Type: "volume", Action: "create", ActorID: "data"},
+		{Time: time.Now(), Type: "container", Action: "oom", ActorID: "bbbbbbbbbbbb", Attributes: map[string]string{"name": "shop-api-1", "com.docker.compose.project": "shop"}},
+	}})
+
+	m, _ = command(t, m, "events")
+	if m.NavMode != models.NavEvents || len(m.Items) != 3 {
+		t.Fatalf("events view: mode %v with %d items", m.NavMode, len(m.Items))
+	}
+	if m.Cursor != 2 {
+		t.Errorf("cursor = %d, want the newest event", m.Cursor)
+	}
+
+	tests := []struct {
+		command string
+		want    int
+	}{
+		{"events type container", 2},
+		{"events clear", 3},
+		{"events action oom,die", 1},
+		{"events clear", 3},
+		{"events project shop", 1},
+		{"events clear", 3},
+		{"events container nginx*", 1},
+	}
+	for _, tt := range tests {
+		m, _ = command(t, m, tt.command)
+		if len(m.Items) != tt.want {
+			t.Errorf(":%s shows %d events, want %d", tt.command, len(m.Items), tt.want)
+		}
+	}
+
+	m, _ = command(t, m, "events since yesterday")
+	if m.StatusMessage == "" || m.EventLoading {
+		t.Errorf("bad since: status %q loading %v", m.StatusMessage, m.EventLoading)
+	}
+}
+
+func TestEventJumpToVolume(t *testing.T) {
+	m := newModel(t, composeFake())
+	m, _ = update(m, models.DockerEventsMsg{Events: []models.DockerEvent{
+		{Time: time.Now(), Type: "volume", Action: "create", ActorID: "data"},
+	}})
+	m, _ = press(t, m, "7", "enter")
+	if m.NavMode != models.NavVolumes || !m.Items[m.Cursor].IsVolume || m.Items[m.Cursor].Volume.Name != "data" {
+		t.Errorf("jump landed on %v %+v", m.NavMode, m.Items[m.Cursor])
+	}
+}
+
+func TestEventJumpToRemovedResourceStaysOnTimeline(t *testing.T) {
+	m := newModel(t, composeFake())
+	m, _ = update(m, models.DockerEventsMsg{Events: []models.DockerEvent{
+		{Time: time.Now(), Type: "volume", Action: "destroy", ActorID: "gone"},
+	}})
+	m, _ = press(t, m, "7", "enter")
+	if m.NavMode != models.NavEvents || !m.Items[m.Cursor].IsEvent {
+		t.Errorf("jump left the timeline for %v", m.NavMode)
+	}
+	if !strings.Contains(m.StatusMessage, "no longer exists") {
+		t.Errorf("status = %q, want the resource reported gone", m.StatusMessage)
+	}
+}
+
+func TestInputModesAreExclusive(t *testing.T) {
+	m := newModel(t, composeFake())
+
+	m, _ = command(t, m, "help")
+	if m.Input != models.InputHelp {
+		t.Fatalf("input = %v after :help, want help", m.Input)
+	}
+	// Keys behind the overlay do nothing
+	m, _ = press(t, m, "j", "2")
+	if m.Cursor != 0 || m.NavMode != models.NavContainers {
+		t.Errorf("keys reached the list behind help: cursor %d mode %v", m.Cursor, m.NavMode)
+	}
+
+	// A command replaces the overlay instead of opening behind it
+	m, _ = press(t, m, ":")
+	if m.Input != models.InputCommand {
+		t.Fatalf("input = %v after :, want command", m.Input)
+	}
+	m, _ = press(t, m, "esc")
+	if m.Input != models.InputNormal {
+		t.Errorf("input = %v after esc, want normal", m.Input)
+	}
+}
+
+// stubServices replaces the start action and records the calls it gets.
+type stubServices struct {
+	docker.Services
+	started []string
+}
+
+func (s *stubServices) StartContainer(m *models.Model) tea.Cmd {
+	s.started = append(s.started, m.SelectedContainer().Name)
+	return nil
+}
+
+func TestNewModelUsesGivenServices(t *testing.T) {
+	fake := composeFake()
+	services := &stubServices{}
+	m := models.NewModel(fake, services, nil)
+	m.KeyBindings = config.Default()
+	m = load(t, m, docker.RefreshContainers)
+
+	m, _ = command(t, m, "start")
+	if len(services.started) != 1 || services.started[0] != "nginx" {
+		t.Errorf("started = %v, want nginx", services.started)
+	}
+	if calls := fake.Calls("ContainerStart"); len(calls) != 0 {
+		t.Errorf("the Docker backend was called: %+v", calls)
+	}
+}
+
+func TestStartupWithoutDaemonReconnects(t *testing.T) {
+	fake := composeFake()
+	fake.SetDown(true)
+	m := models.NewModel(fake, docker.Services{}, nil)
+	m.KeyBindings = config.Default()
+	m.StartLoading()
+
+	// The lists load from Init and all fail
+	failed := waitFor[models.ResourceFailedMsg](t, m.Init())
+	m, retry := update(m, failed)
+	if !m.Disconnected || retry == nil {
+		t.Fatalf("disconnected %v, retry scheduled %v", m.Disconnected, retry != nil)
+	}
+	if m.LoadErrors[failed.Resource] == "" {
+		t.Errorf("no inline error for %s", failed.Resource)
+	}
+
+	// Docker comes back before the next attempt
+	fake.SetDown(false)
+	m, cmd := update(m, models.DaemonReconnectMsg{})
+	if !m.Reconnecting {
+		t.Error("reconnect attempt not shown")
+	}
+	m, cmd = update(m, waitFor[models.ContainersRefreshedMsg](t, cmd))
+	if m.Disconnected || m.Reconnecting || len(m.Containers) != 3 {
+		t.Fatalf("after reconnect: disconnected %v reconnecting %v, %d containers", m.Disconnected, m.Reconnecting, len(m.Containers))
+	}
+	if m.EventStream == nil {
+		t.Fatal("events not resubscribed after reconnect")
+	}
+	defer m.EventStream.Cancel()
+	m, _ = update(m, waitFor[models.VolumesLoadedMsg](t, cmd))
+	if len(m.Volumes) != 1 || m.LoadErrors[models.ResourceVolumes] != "" || m.Loading[models.ResourceVolumes] {
+		t.Errorf("volumes not reloaded: %+v", m.Volumes)
+	}
+}
+
+func TestOneFailingListKeepsTheOthers(t *testing.T) {
+	fake := composeFake()
+	fake.Fail("ImageList", errAction)
+	m := models.NewModel(fake, docker.Services{}, nil)
+	m.KeyBindings = config.Default()
+
+	m, _ = update(m, docker.ReloadImages(&m)())
+	m, _ = update(m, docker.ReloadNetworks(&m)())
+	if m.Disconnected {
+		t.Error("a rejected request marked the daemon unreachable")
+	}
+	if m.LoadErrors[models.ResourceImages] != errAction.Error() || len(m.Networks) != 1 {
+		t.Errorf("load errors %v, networks %+v", m.LoadErrors, m.Networks)
+	}
+}
+
+// hostServices connects :context to in-memory daemons by name.
+type hostServices struct {
+	docker.Services
+	hosts map[string]*dockertest.Fake
+}
+
+func (s hostServices) Connect(_ *models.Model, c models.DockerContext) (models.DockerAPI, error) {
+	if fake, ok := s.hosts[c.Name]; ok {
+		return fake, nil
+	}
+	return nil, errors.New("connection refused")
+}
+
+func TestSwitchContext(t *testing.T) {
+	local := composeFake()
+	remote := dockertest.New()
+	m := models.NewModel(local, hostServices{hosts: map[string]*dockertest.Fake{"prod": remote}}, nil)
+	m.KeyBindings = config.Default()
+	m.Contexts = []models.DockerContext{{Name: "default"}, {Name: "prod"}, {Name: "broken"}}
+	m.Context = "default"
+	m, _ = update(m, docker.RefreshContainers(&m)())
+	// Same ID and creation time as nginx, so only a per-daemon environment
+	// cache tells them apart
+	remote.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "billing", Created: m.Containers[0].Created, Env: []string{"REGION=eu"}})
+	stale := docker.ReloadVolumes(&m) // Still running when the context changes
+
+	m, _ = command(t, m, "context")
+	if m.StatusMessage != "Contexts: default (active), prod, broken" {
+		t.Errorf("status = %q", m.StatusMessage)
+	}
+	m, _ = command(t, m, "context broken")
+	if m.Context != "default" || m.DockerClient != local || m.StatusMessage != "Cannot connect to broken: connection refused" {
+		t.Errorf("failed switch: context %q, status %q", m.Context, m.StatusMessage)
+	}
+
+	m, cmd := command(t, m, "context prod")
+	if m.Context != "prod" || m.DockerClient != remote || len(m.Containers) != 0 || !m.Loading[models.ResourceContainers] {
+		t.Fatalf("after switch: context %q, %d containers, loading %v", m.Context, len(m.Containers), m.Loading)
+	}
+	if m.EventStream == nil {
+		t.Fatal("no event stream for the new daemon")
+	}
+	defer m.EventStream.Cancel()
+
+	m, _ = update(m, stale())
+	if len(m.Volumes) != 0 {
+		t.Errorf("volumes of the previous daemon shown: %+v", m.Volumes)
+	}
+	m, _ = update(m, waitFor[models.ContainersRefreshedMsg](t, cmd))
+	if len(m.Containers) != 1 || m.Containers[0].Name != "billing" {
+		t.Fatalf("containers = %+v, want billing", m.Containers)
+	}
+	if env := m.Containers[0].Env; len(env) != 1 || env[0] != "REGION=eu" {
+		t.Errorf("billing env = %v, want the remote daemon's", env)
+	}
+}
+
+func TestTopStatsOfPreviousDaemonAreDropped(t *testing.T) {
+	local := dockertest.New()
+	local.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "nginx", Stats: []container.StatsResponse{{}}})
+	remote := dockertest.New()
+	m := models.NewModel(local, hostServices{hosts: map[string]*dockertest.Fake{"prod": remote}}, nil)
+	m.KeyBindings = config.Default()
+	m.Contexts = []models.DockerContext{{Name: "default"}, {Name: "prod"}}
+	m.Context = "default"
+	m, _ = update(m, docker.RefreshContainers(&m)())
+
+	m, cmd := press(t, m, "5")
+	stale := waitFor[models.TopStatsLoadedMsg](t, cmd) // Delivered after the context changes
+	m, _ = command(t, m, "context prod")
+	if m.EventStream != nil {
+		defer m.EventStream.Cancel()
+	}
+	m, _ = update(m, stale)
+	if len(m.TopStats) != 0 {
+		t.Errorf("top stats of the previous daemon shown: %+v", m.TopStats)
+	}
+	if m.NavMode != models.NavTop || !m.TopLoading {
+		t.Errorf("overview after switch: mode %v, loading %v", m.NavMode, m.TopLoading)
+	}
+}
+
+func TestOlderLogsForPreviousContainerAreDropped(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: []dockertest.LogLine{
+		{Time: start, Text: "web 1"},
+		{Time: start.Add(time.Second), Text: "web 2"},
+		{Time: start.Add(2 * time.Second), Text: "web 3"},
+	}})
+	fake.AddContainer(dockertest.Container{ID: "bbbbbbbbbbbb", Name: "worker", Logs: []dockertest.LogLine{
+		{Time: start, Text: "worker 1"},
+	}})
+	m := newModel(t, fake)
+	logs := config.DefaultLogs()
+	logs.TailLines, logs.PageSize = 1, 1
+	m.LogsConfig = logs
+
+	m, cmd := press(t, m, "l")
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	m, older := press(t, m, "K")
+	if !m.LoadingOlder {
+		t.Fatal("K did not request an older page")
+	}
+
+	page := waitFor[models.OperationDoneMsg](t, older)
+
+	// Leave for the other container's logs before the page is delivered.
+	// The first esc cancels the fetch, which has already returned.
+	m, _ = press(t, m, "esc", "esc", "j")
+	m, cmd = press(t, m, "l")
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	m, _ = update(m, page)
+
+	if len(m.Logs) != 1 || m.Logs[0].Message() != "worker 1" {
+		t.Errorf("logs = %+v, want only worker's line", m.Logs)
+	}
+	if m.LogsAtStart {
+		t.Error("the stale page marked worker's log as fully loaded")
+	}
+}
+
+func TestFollowedLinesKeepScrollAndMatch(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var lines []dockertest.LogLine
+	for i, text := range []string{"boot", "err one", "ok", "err two", "ok", "ok"} {
+		lines = append(lines, dockertest.LogLine{Time: start.Add(time.Duration(i) * time.Second), Text: text})
+	}
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: lines})
+	m := newModel(t, fake)
+	m, cmd := press(t, m, "l")
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+	m, _ = press(t, m, "?", "e", "r", "r", "enter", "n")
+	if m.SearchResultIdx != 1 || m.LogScroll != 3 {
+		t.Fatalf("match %d at line %d, want the second match at line 3", m.SearchResultIdx, m.LogScroll)
+	}
+
+	stream := &models.LogStream{}
+	m.LogStream = stream
+	m, _ = update(m, models.LogLineMsg{Stream: stream, Lines: []models.LogEntry{{Line: "err three"}, {Line: "ok"}}})
+	if m.LogScroll != 3 || m.SearchResultIdx != 1 || len(m.SearchResults) != 3 {
+		t.Errorf("after a batch: match %d of %d at line %d, want match 1 of 3 at line 3",
+			m.SearchResultIdx, len(m.SearchResults), m.LogScroll)
+	}
+
+	m, _ = press(t, m, "G")
+	m, _ = update(m, models.LogLineMsg{Stream: stream, Lines: []models.LogEntry{{Line: "ok"}}})
+	if want := len(m.VisibleLogs) - 1; m.LogScroll != want {
+		t.Errorf("scroll = %d, want the bottom (%d) while at the bottom", m.LogScroll, want)
+	}
+}
+
+func TestWriteLogsKeepsSpacesInPath(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web", Logs: []dockertest.LogLine{
+		{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Text: "hello"},
+	}})
+	m := newModel(t, fake)
+	m, cmd := press(t, m, "l")
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+
+	path := filepath.Join(t.TempDir(), "web  logs -T.txt")
+	_, cmd = command(t, m, "w -T "+path)
+	saved := waitFor[models.LogsSavedMsg](t, cmd)
+	if saved.Err != nil || saved.Path != path {
+		t.Fatalf("saved %q, err %v; want %q", saved.Path, saved.Err, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello\n" {
+		t.Errorf("file = %q, want the line without its timestamp", data)
+	}
+}
+
+func TestExportLogsReportsUncreatableFile(t *testing.T) {
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{ID: "aaaaaaaaaaaa", Name: "web"})
+	m := newModel(t, fake)
+	m, cmd := press(t, m, "l")
+	m, _ = update(m, waitFor[models.OperationDoneMsg](t, cmd))
+
+	path := filepath.Join(t.TempDir(), "missing", "web.log")
+	m, cmd = command(t, m, "w! "+path)
+	if m.LogExport == nil {
+		t.Fatal("no export started")
+	}
+	m, _ = update(m, waitFor[models.LogExportMsg](t, cmd))
+	if m.LogExport != nil || !strings.HasPrefix(m.StatusMessage, "Log export to "+path+" failed") {
+		t.Errorf("status = %q, export %v", m.StatusMessage, m.LogExport)
+	}
+}
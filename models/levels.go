@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LogLevel is the severity detected for a log line. Lines without a
+// recognisable level are LevelUnknown and are never hidden by level filters,
+// since they are often continuations such as stack traces.
+type LogLevel int
+
+const (
+	LevelUnknown LogLevel = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "all"
+	}
+}
+
+// levelPrefixLen bounds how far into a line the built-in patterns look, so
+// a word like ERROR deep inside a message does not reclassify it.
+const levelPrefixLen = 80
+
+type levelRule struct {
+	level   LogLevel
+	pattern *regexp.Regexp
+}
+
+// LevelClassifier detects log levels from JSON fields, logfmt pairs, common
+// prefixes and user-configured patterns. A nil classifier uses the defaults.
+type LevelClassifier struct {
+	custom []levelRule
+}
+
+var (
+	logfmtLevel = regexp.MustCompile(`(?:^|\s)(?:level|lvl|severity)="?([A-Za-z]+)`)
+
+	builtinLevelRules = []levelRule{
+		{LevelError, regexp.MustCompile(`\b(ERROR|ERR|FATAL|PANIC|CRITICAL|CRIT)\b|\[(error|crit|alert|emerg)\]|^[EF]\d{4} `)},
+		{LevelWarn, regexp.MustCompile(`\b(WARN|WARNING)\b|\[warn(ing)?\]|^W\d{4} `)},
+		{LevelInfo, regexp.MustCompile(`\b(INFO|NOTICE)\b|\[(info|notice)\]|^I\d{4} `)},
+		{LevelDebug, regexp.MustCompile(`\b(DEBUG|TRACE)\b|\[(debug|trace)\]`)},
+	}
+)
+
+// NewLevelClassifier compiles user patterns keyed by level name
+// ("error", "warn", "info", "debug"). They are tried before the built-in rules.
+func NewLevelClassifier(patterns map[string][]string) (*LevelClassifier, error) {
+	c := &LevelClassifier{}
+	// Check the most severe levels first so overlapping patterns favour them
+	for _, level := range []LogLevel{LevelError, LevelWarn, LevelInfo, LevelDebug} {
+		for name, exprs := range patterns {
+			if levelFromName(name) != level {
+				continue
+			}
+			for _, expr := range exprs {
+				re, err := regexp.Compile(expr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s level pattern %q: %w", name, expr, err)
+				}
+				c.custom = append(c.custom, levelRule{level: level, pattern: re})
+			}
+		}
+	}
+	for name := range patterns {
+		if levelFromName(name) == LevelUnknown {
+			return nil, fmt.Errorf("unknown log level %q in level_patterns", name)
+		}
+	}
+	return c, nil
+}
+
+// Classify returns the level of a log entry.
+func (c *LevelClassifier) Classify(e LogEntry) LogLevel {
+	msg := e.Message()
+
+	if c != nil {
+		for _, rule := range c.custom {
+			if rule.pattern.MatchString(msg) {
+				return rule.level
+			}
+		}
+	}
+
+	if fields, ok := e.JSON(); ok {
+		for _, key := range []string{"level", "lvl", "severity", "loglevel"} {
+			if v, ok := fields[key]; ok {
+				return levelFromValue(v)
+			}
+		}
+	}
+
+	if m := logfmtLevel.FindStringSubmatch(msg); m != nil {
+		if level := levelFromName(m[1]); level != LevelUnknown {
+			return level
+		}
+	}
+
+	if len(msg) > levelPrefixLen {
+		msg = msg[:levelPrefixLen]
+	}
+	for _, rule := range builtinLevelRules {
+		if rule.pattern.MatchString(msg) {
+			return rule.level
+		}
+	}
+	return LevelUnknown
+}
+
+// levelFromValue maps a JSON level field to a LogLevel. Numeric levels follow
+// the bunyan/pino convention (20 debug, 30 info, 40 warn, 50+ error).
+func levelFromValue(v any) LogLevel {
+	switch val := v.(type) {
+	case string:
+		return levelFromName(val)
+	case float64:
+		switch {
+		case val >= 50:
+			return LevelError
+		case val >= 40:
+			return LevelWarn
+		case val >= 30:
+			return LevelInfo
+		case val > 0:
+			return LevelDebug
+		}
+	}
+	return LevelUnknown
+}
+
+func levelFromName(name string) LogLevel {
+	switch strings.ToLower(name) {
+	case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emerg", "emergency":
+		return LevelError
+	case "warn", "warning":
+		return LevelWarn
+	case "info", "information", "notice":
+		return LevelInfo
+	case "debug", "trace", "verbose":
+		return LevelDebug
+	default:
+		return LevelUnknown
+	}
+}
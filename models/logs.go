@@ -0,0 +1,434 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLogBatch caps how many buffered lines are folded into one LogLineMsg.
+const maxLogBatch = 500
+
+// Next waits for the next batch of lines on the stream. It blocks for the
+// first line and then drains whatever else is already buffered.
+func (s *LogStream) Next() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-s.Lines
+		if !ok {
+			return LogStreamEndedMsg{Stream: s, Err: <-s.Done}
+		}
+
+		batch := []LogEntry{line}
+		for len(batch) < maxLogBatch {
+			select {
+			case line, ok := <-s.Lines:
+				if !ok {
+					return LogLineMsg{Stream: s, Lines: batch}
+				}
+				batch = append(batch, line)
+			default:
+				return LogLineMsg{Stream: s, Lines: batch}
+			}
+		}
+		return LogLineMsg{Stream: s, Lines: batch}
+	}
+}
+
+// Next waits for the next progress update of the export.
+func (e *LogExport) Next() tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-e.Updates
+		if !ok {
+			p = LogExportProgress{Done: true}
+		}
+		return LogExportMsg{Export: e, Progress: p}
+	}
+}
+
+// formatExportSize renders an export's size for the status bar.
+func formatExportSize(bytes int64) string {
+	if bytes < 1<<20 {
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+}
+
+// logsView is the sub-model of the log view. It owns the log stream, older
+// pages, saving and exporting, and the search prompt.
+type logsView struct{}
+
+func (logsView) View(m *Model, width, height int) string {
+	return m.Renderer.Logs(m, width, height)
+}
+
+func (logsView) Update(m *Model, msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case LogsLoadedMsg:
+		stopLogStream(m)
+		m.Logs = msg.Lines
+		if maxLines := m.LogsSettings().MaxLines; len(m.Logs) > maxLines {
+			m.Logs = m.Logs[len(m.Logs)-maxLines:]
+		}
+		m.LogsAtStart = false
+		m.LoadingOlder = false
+		m.LogGeneration++
+		m.LogTargets = msg.Targets
+		m.LogProject = msg.Project
+		m.HiddenSources = nil
+		m.VisibleLogs = nil
+		rebuildVisibleLogs(m)
+		m.LogScroll = len(m.VisibleLogs) - 1 // Scroll to bottom
+		m.ViewMode = ViewLogs
+		m.FollowingLogs = msg.Follow
+		if m.FollowingLogs {
+			return m.Services.FollowLogs(m), true
+		}
+		return nil, true
+
+	case LogLineMsg:
+		// Drop batches from a stream that was cancelled in the meantime.
+		if msg.Stream != m.LogStream {
+			return nil, true
+		}
+		// Only follow the new lines when the view was already at the bottom.
+		atBottom := m.LogScroll >= len(m.VisibleLogs)-1
+		match := currentMatchLine(m)
+		count := len(m.Logs) + len(msg.Lines)
+		for _, entry := range msg.Lines {
+			m.Logs = append(m.Logs, entry)
+			if logEntryVisible(m, entry) {
+				m.VisibleLogs = append(m.VisibleLogs, len(m.Logs)-1)
+			}
+		}
+		trimLogs(m)
+		if atBottom {
+			m.LogScroll = len(m.VisibleLogs) - 1
+		}
+		if m.SearchQuery != "" {
+			refreshSearch(m, match-(count-len(m.Logs)))
+		}
+		return msg.Stream.Next(), true
+
+	case LogStreamEndedMsg:
+		if msg.Stream != m.LogStream {
+			return nil, true
+		}
+		m.LogStream = nil
+		m.FollowingLogs = false
+		if msg.Err != nil {
+			m.StatusMessage = fmt.Sprintf("Log follow stopped: %v", msg.Err)
+		} else {
+			m.StatusMessage = "Log stream closed"
+		}
+		return nil, true
+
+	case OlderLogsLoadedMsg:
+		// Drop pages fetched for a buffer that was replaced in the meantime.
+		if msg.Generation != m.LogGeneration {
+			return nil, true
+		}
+		m.LoadingOlder = false
+		if m.ViewMode != ViewLogs {
+			return nil, true
+		}
+		if msg.Err != nil {
+			m.StatusMessage = fmt.Sprintf("Failed to load older logs: %v", msg.Err)
+			return nil, true
+		}
+		if len(msg.Lines) == 0 {
+			if !msg.AtStart {
+				m.StatusMessage = "No older lines found; too many new lines since the log was loaded"
+				return nil, true
+			}
+			m.LogsAtStart = true
+			m.StatusMessage = "Reached the beginning of the log"
+			return nil, true
+		}
+
+		// Reading history while following would let the cap trim it again right away.
+		paused := m.FollowingLogs
+		if paused {
+			stopLogStream(m)
+			m.FollowingLogs = false
+		}
+
+		// Shift existing indices so the scroll position stays on the same line.
+		shift := len(msg.Lines)
+		match := currentMatchLine(m)
+		if match >= 0 {
+			match += shift
+		}
+		m.Logs = append(append([]LogEntry(nil), msg.Lines...), m.Logs...)
+		for i := range m.VisibleLogs {
+			m.VisibleLogs[i] += shift
+		}
+		rebuildVisibleLogs(m)
+		if m.SearchQuery != "" {
+			refreshSearch(m, match)
+		}
+		m.StatusMessage = fmt.Sprintf("Loaded %d older lines", shift)
+		if paused {
+			m.StatusMessage += " • follow paused"
+		}
+		return nil, true
+
+	case LogsSavedMsg:
+		if msg.Err != nil {
+			m.StatusMessage = fmt.Sprintf("Failed to save logs: %v", msg.Err)
+		} else {
+			m.StatusMessage = fmt.Sprintf("Wrote %d lines to %s", msg.Lines, msg.Path)
+		}
+		return nil, true
+
+	case LogExportMsg:
+		if msg.Export != m.LogExport {
+			return nil, true
+		}
+		p := msg.Progress
+		if !p.Done {
+			m.StatusMessage = fmt.Sprintf("Saving full log to %s: %d lines (%s)...", msg.Export.Path, p.Lines, formatExportSize(p.Bytes))
+			return msg.Export.Next(), true
+		}
+		m.LogExport = nil
+		if p.Err != nil {
+			m.StatusMessage = fmt.Sprintf("Log export to %s failed after %d lines: %v", msg.Export.Path, p.Lines, p.Err)
+		} else {
+			m.StatusMessage = fmt.Sprintf("Saved %d lines (%s) to %s", p.Lines, formatExportSize(p.Bytes), msg.Export.Path)
+		}
+		return nil, true
+
+	case tea.KeyMsg:
+		if m.Input != InputSearch {
+			return nil, false
+		}
+		switch msg.String() {
+		case "enter":
+			// Execute search
+			m.Input = InputNormal
+			performSearch(m)
+		case "esc":
+			// Cancel search
+			m.Input = InputNormal
+			m.SearchQuery = ""
+			m.SearchResults = nil
+			clearSearchRegex(m)
+			m.StatusMessage = ""
+		case "backspace":
+			if len(m.SearchQuery) > 0 {
+				m.SearchQuery = m.SearchQuery[:len(m.SearchQuery)-1]
+			}
+		default:
+			// Add character to search query
+			if len(msg.String()) == 1 {
+				m.SearchQuery += msg.String()
+			}
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// reset clears the log view on leaving it. Preferences such as the JSON
+// view, smart case and search context stay, and so does a running export.
+// The generation is kept so pages still in flight stay recognisably stale.
+func (s *LogsState) reset() {
+	if s.LogStream != nil {
+		s.LogStream.Cancel()
+	}
+	*s = LogsState{
+		LogsConfig:      s.LogsConfig,
+		LevelClassifier: s.LevelClassifier,
+		LogJSONView:     s.LogJSONView,
+		LogExport:       s.LogExport,
+		LogGeneration:   s.LogGeneration,
+		SearchState: SearchState{
+			SearchSmartCase: s.SearchSmartCase,
+			SearchContext:   s.SearchContext,
+		},
+	}
+}
+
+// stopLogStream cancels the active log subscription, if any.
+func stopLogStream(m *Model) {
+	if m.LogStream != nil {
+		m.LogStream.Cancel()
+		m.LogStream = nil
+	}
+}
+
+// rebuildVisibleLogs recomputes which log entries pass the active filters,
+// keeping the scroll position on the same entry where possible.
+func rebuildVisibleLogs(m *Model) {
+	current := -1
+	if m.LogScroll >= 0 && m.LogScroll < len(m.VisibleLogs) {
+		current = m.VisibleLogs[m.LogScroll]
+	}
+
+	visible := make([]int, 0, len(m.Logs))
+	for i, entry := range m.Logs {
+		if logEntryVisible(m, entry) {
+			visible = append(visible, i)
+		}
+	}
+	m.LogContextBreaks = nil
+	if m.SearchFilter && m.SearchRegex != nil {
+		visible = applySearchFilter(m, visible)
+	}
+	m.VisibleLogs = visible
+
+	m.LogScroll = len(visible) - 1
+	if current >= 0 {
+		if pos := sort.SearchInts(visible, current); pos < len(visible) {
+			m.LogScroll = pos
+		}
+	}
+}
+
+// trimLogs drops the oldest entries once the buffer passes the configured
+// cap. A little slack avoids reslicing on every followed batch.
+func trimLogs(m *Model) {
+	maxLines := m.LogsSettings().MaxLines
+	if len(m.Logs) <= maxLines+maxLines/10 {
+		return
+	}
+
+	drop := len(m.Logs) - maxLines
+	m.Logs = m.Logs[drop:]
+
+	visible := make([]int, 0, len(m.VisibleLogs))
+	for _, idx := range m.VisibleLogs {
+		if idx >= drop {
+			visible = append(visible, idx-drop)
+		}
+	}
+	m.LogScroll -= len(m.VisibleLogs) - len(visible)
+	m.VisibleLogs = visible
+	if m.LogScroll < 0 {
+		m.LogScroll = 0
+	}
+	m.LogsAtStart = false
+}
+
+// logEntryVisible reports whether an entry passes the stream, source and
+// JSON field filters.
+func logEntryVisible(m *Model, entry LogEntry) bool {
+	if !m.LogStreamFilter.Allows(entry.Stream) || m.HiddenSources[entry.Source] {
+		return false
+	}
+	if entry.Level != LevelUnknown && entry.Level < m.LogMinLevel {
+		return false
+	}
+	if len(m.LogFieldFilters) == 0 {
+		return true
+	}
+
+	fields, ok := entry.JSON()
+	if !ok {
+		return false
+	}
+	for _, f := range m.LogFieldFilters {
+		if !f.Matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// Message returns the line without its leading Docker timestamp.
+func (e LogEntry) Message() string {
+	if e.Time.IsZero() {
+		return e.Line
+	}
+	if _, rest, ok := strings.Cut(e.Line, " "); ok {
+		return rest
+	}
+	return ""
+}
+
+// JSON parses the message as a JSON object. ok is false for plain-text lines.
+func (e LogEntry) JSON() (map[string]any, bool) {
+	msg := e.Message()
+	if len(msg) < 2 || msg[0] != '{' {
+		return nil, false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(msg), &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// LookupField resolves a key in a JSON object. Dotted keys such as
+// "http.status" walk into nested objects when no literal key matches.
+func LookupField(fields map[string]any, key string) (any, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := fields[head].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return LookupField(nested, rest)
+}
+
+// FormatFieldValue renders a JSON value for display and filter matching.
+func FormatFieldValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
+
+// Matches reports whether the JSON object satisfies the filter. Values are
+// compared case-insensitively.
+func (f FieldFilter) Matches(fields map[string]any) bool {
+	v, ok := LookupField(fields, f.Key)
+	match := ok && strings.EqualFold(FormatFieldValue(v), f.Value)
+	return match != f.Negate
+}
+
+func (f FieldFilter) String() string {
+	if f.Negate {
+		return f.Key + "!=" + f.Value
+	}
+	return f.Key + "=" + f.Value
+}
+
+// parseFieldFilters parses terms like "level=error" or "service!=api".
+func parseFieldFilters(terms []string) ([]FieldFilter, error) {
+	var filters []FieldFilter
+	for _, term := range terms {
+		if key, value, ok := strings.Cut(term, "!="); ok && key != "" {
+			filters = append(filters, FieldFilter{Key: key, Value: value, Negate: true})
+			continue
+		}
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid filter %q: use key=value or key!=value", term)
+		}
+		filters = append(filters, FieldFilter{Key: key, Value: value})
+	}
+	return filters, nil
+}
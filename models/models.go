@@ -1,48 +1,11 @@
 package models
 
 import (
-	"gdocker/config"
+	"context"
+	"strings"
 	"time"
-
-	"github.com/moby/moby/client"
 )
 
-type Model struct {
-	KeyBindings     *config.KeyBindings
-	UIConfig        *config.UIConfig
-	Containers      []Container
-	Standalone      []Container
-	Projects        []ComposeGroup
-	Items           []ListItem
-	Cursor          int
-	SelectedPort    int // For port selection to open in browser
-	Width           int
-	Height          int
-	NavMode         NavigationMode
-	ViewMode        ViewMode
-	AutoRefreshSecs int
-	Logs            []string
-	LogScroll       int
-	LogSince        time.Time
-	StatusMessage   string
-	DockerClient    *client.Client
-	SearchMode      bool   // Whether we're in search input mode
-	SearchQuery     string // Current search query
-	SearchResults   []int  // Line indices that match the search
-	SearchResultIdx int    // Current position in SearchResults
-	CommandMode     bool   // Whether we're in command mode (:)
-	CommandInput    string // Current command input
-	HelpMode        bool   // Whether we're in help view
-	Stats           *ContainerStats
-	Volumes         []Volume
-	Images          []Image
-	Networks        []Network
-	VolumeFiles     []string // Files in current volume directory
-	VolumePath      string   // Current path in volume
-	InspectData     string   // JSON inspect data
-	FollowingLogs   bool     // Whether logs are being followed
-}
-
 type PortMapping struct {
 	PrivatePort uint16
 	PublicPort  uint16
@@ -50,14 +13,30 @@ type PortMapping struct {
 	IP          string
 }
 
+// ContainerStats is one stats sample of a container. Byte counters are
+// cumulative since the container started; rates are per second since the
+// previous sample and zero on the first one.
 type ContainerStats struct {
-	CPUPerc     string
-	MemUsage    string
-	MemPerc     string
-	NetIO       string
-	BlockIO     string
-	PIDs        string
 	ContainerID string
+	Time        time.Time
+	CPUPercent  float64
+	MemUsage    uint64
+	MemLimit    uint64
+	MemPercent  float64
+	NetRx       uint64
+	NetTx       uint64
+	BlockRead   uint64
+	BlockWrite  uint64
+	PIDs        uint64
+
+	// Raw CPU counters, used to compute CPUPercent between one-shot samples
+	CPUTotalUsage  uint64
+	SystemCPUUsage uint64
+
+	NetRxRate      float64
+	NetTxRate      float64
+	BlockReadRate  float64
+	BlockWriteRate float64
 }
 
 // Container holds container info
@@ -68,9 +47,27 @@ type Container struct {
 	State   string
 	Status  string
 	Project string
+	Service string // Compose service, empty outside compose projects
 	Created time.Time
 	Ports   []PortMapping
 	Env     []string
+
+	ImageID  string   // Short ID of the image
+	Volumes  []string // Names of the mounted volumes
+	Networks []string // Names of the attached networks
+}
+
+// ServiceName is the label used for a container within its compose project.
+// Compose names like "shop-web-1" are shortened to "web-1".
+func (c Container) ServiceName() string {
+	if c.Project != "" {
+		for _, sep := range []string{"-", "_"} {
+			if name, ok := strings.CutPrefix(c.Name, c.Project+sep); ok && name != "" {
+				return name
+			}
+		}
+	}
+	return c.Name
 }
 
 // Volume holds volume info
@@ -116,11 +113,15 @@ type ListItem struct {
 	IsVolume    bool
 	IsImage     bool
 	IsNetwork   bool
+	IsAlert     bool
+	IsEvent     bool
 	Project     *ComposeGroup
 	Container   *Container
 	Volume      *Volume
 	Image       *Image
 	Network     *Network
+	Alert       *Alert
+	Event       *DockerEvent
 	Index       int // Index in the projects/containers array
 }
 
@@ -132,6 +133,9 @@ const (
 	NavVolumes
 	NavImages
 	NavNetworks
+	NavTop
+	NavAlerts
+	NavEvents
 )
 
 // ViewMode represents what's shown in the right panel
@@ -149,22 +153,136 @@ const (
 
 // Messages
 type ContainersRefreshedMsg struct {
+	Client     DockerAPI // Daemon the list came from
 	Containers []Container
 }
 
+// StreamType identifies which container output stream a log line came from.
+type StreamType int
+
+const (
+	StreamStdout StreamType = iota
+	StreamStderr
+)
+
+// LogEntry is a single log line together with the stream it was written to.
+type LogEntry struct {
+	Stream StreamType
+	Source string    // Name of the container that wrote the line
+	Time   time.Time // Docker timestamp, zero if the line had none
+	Level  LogLevel
+	Line   string
+}
+
+// FieldFilter matches a JSON log field against a value, e.g. level=error.
+type FieldFilter struct {
+	Key    string
+	Value  string
+	Negate bool
+}
+
+// LogTarget is a container whose output feeds the log view.
+type LogTarget struct {
+	ID     string
+	Source string
+	TTY    bool // TTY output is not multiplexed, so stdout/stderr are merged
+}
+
+// StreamFilter selects which output streams the log view shows.
+type StreamFilter int
+
+const (
+	StreamFilterAll StreamFilter = iota
+	StreamFilterStdout
+	StreamFilterStderr
+)
+
+func (f StreamFilter) String() string {
+	switch f {
+	case StreamFilterStdout:
+		return "stdout"
+	case StreamFilterStderr:
+		return "stderr"
+	default:
+		return "all"
+	}
+}
+
+// Allows reports whether entries from the given stream pass the filter.
+func (f StreamFilter) Allows(stream StreamType) bool {
+	switch f {
+	case StreamFilterStdout:
+		return stream == StreamStdout
+	case StreamFilterStderr:
+		return stream == StreamStderr
+	default:
+		return true
+	}
+}
+
 type LogsLoadedMsg struct {
-	Lines  []string
-	Follow bool
-	Since  time.Time
+	Lines   []LogEntry
+	Follow  bool
+	Targets []LogTarget
+	Project string // Set when the lines are merged from a compose project
 }
 
+// OlderLogsLoadedMsg carries a page of lines older than the current buffer.
+type OlderLogsLoadedMsg struct {
+	Lines      []LogEntry
+	AtStart    bool // Every target's log was read back to its first line
+	Err        error
+	Generation int // LogGeneration of the buffer the page was fetched for
+}
+
+// LogStream is a long-lived log subscription. Producers push lines into
+// Lines and report the terminal error on Done before closing Lines.
+type LogStream struct {
+	Lines  <-chan LogEntry
+	Done   <-chan error
+	Cancel context.CancelFunc
+}
+
+// LogLineMsg delivers a batch of lines read from a LogStream.
 type LogLineMsg struct {
-	Line string
+	Stream *LogStream
+	Lines  []LogEntry
 }
 
-type LogsFollowedMsg struct {
-	Lines []string
-	Since time.Time
+// LogStreamEndedMsg is sent once a LogStream has no more lines.
+type LogStreamEndedMsg struct {
+	Stream *LogStream
+	Err    error
+}
+
+// LogsSavedMsg reports the result of writing the log buffer with :w.
+type LogsSavedMsg struct {
+	Path  string
+	Lines int
+	Err   error
+}
+
+// LogExport is a background copy of the full log history of the log
+// targets into a file, started with :w!.
+type LogExport struct {
+	Path    string
+	Updates <-chan LogExportProgress
+	Cancel  context.CancelFunc
+}
+
+// LogExportProgress is how far a LogExport has got. The last update has
+// Done set, with Err if the export failed.
+type LogExportProgress struct {
+	Lines int
+	Bytes int64
+	Done  bool
+	Err   error
+}
+
+// LogExportMsg delivers a LogExport progress update.
+type LogExportMsg struct {
+	Export   *LogExport
+	Progress LogExportProgress
 }
 
 type ActionResultMsg struct {
@@ -172,20 +290,63 @@ type ActionResultMsg struct {
 	Success bool
 }
 
-type StatsLoadedMsg struct {
-	Stats *ContainerStats
+// TopStatsLoadedMsg delivers one round of overview stats.
+type TopStatsLoadedMsg struct {
+	Generation int
+	Stats      map[string]ContainerStats
+	Failed     int
+}
+
+// ProjectStatsLoadedMsg delivers one round of samples for a project panel.
+type ProjectStatsLoadedMsg struct {
+	Project string
+	Stats   map[string]ContainerStats
+	Failed  int
+}
+
+// ProjectStatsTickMsg checks whether a project panel needs fresh stats.
+type ProjectStatsTickMsg struct{}
+
+// TopTickMsg starts the next round of overview stats.
+type TopTickMsg struct {
+	Generation int
+}
+
+// StatsStream is a live ContainerStats subscription for the stats view.
+type StatsStream struct {
+	Samples <-chan ContainerStats
+	Done    <-chan error
+	Cancel  context.CancelFunc
+}
+
+// StatsSampleMsg delivers a sample read from a StatsStream.
+type StatsSampleMsg struct {
+	Stream *StatsStream
+	Stats  ContainerStats
+}
+
+// StatsStreamEndedMsg is sent once a StatsStream has no more samples.
+type StatsStreamEndedMsg struct {
+	Stream *StatsStream
+	Err    error
 }
 
 type VolumesLoadedMsg struct {
+	Client  DockerAPI // Daemon the list came from
 	Volumes []Volume
+	Message string // Status to show, e.g. after a delete
 }
 
 type ImagesLoadedMsg struct {
-	Images []Image
+	Client  DockerAPI // Daemon the list came from
+	Images  []Image
+	Message string
 }
 
 type NetworksLoadedMsg struct {
+	Client   DockerAPI // Daemon the list came from
 	Networks []Network
+	Message  string // Status to show, e.g. after a delete
 }
 
 type InspectLoadedMsg struct {
@@ -198,5 +359,3 @@ type VolumeFilesMsg struct {
 }
 
 type AutoRefreshTickMsg struct{}
-
-type LogFollowTickMsg struct{}
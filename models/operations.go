@@ -0,0 +1,129 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Operation is a Docker request the user is waiting on. It runs under its
+// own context, bounded by a timeout, so esc or :cancel can abort it.
+//
+// Background loads are not operations: list refreshes, reloads after
+// events and the periodic top, project and alert stats. Nobody waits on
+// them, their timeout bounds them, and the next refresh repeats them, so
+// they would only fill the status bar with a spinner.
+type Operation struct {
+	ID      int
+	Label   string // What is being done, e.g. "Stopping web-1"
+	Started time.Time
+	cancel  context.CancelFunc
+}
+
+// OperationsState holds the operations in flight and the spinner that
+// shows them in the status bar
+type OperationsState struct {
+	Operations   []*Operation // Oldest first
+	SpinnerFrame int
+	nextID       int
+	spinning     bool // A spinner tick is scheduled
+}
+
+// OperationDoneMsg delivers the result of an operation once it returned.
+type OperationDoneMsg struct {
+	ID  int
+	Msg tea.Msg
+}
+
+// OperationTickMsg advances the status bar spinner.
+type OperationTickMsg struct{}
+
+const operationTickInterval = 100 * time.Millisecond
+
+// StartOperation runs fn as a tracked operation. A positive timeout bounds
+// the context fn runs under; its result is delivered like any other
+// message once fn returns, also after the operation was cancelled.
+func (s *OperationsState) StartOperation(label string, timeout time.Duration, fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	s.nextID++
+	op := &Operation{ID: s.nextID, Label: label, Started: time.Now(), cancel: cancel}
+	s.Operations = append(s.Operations, op)
+
+	run := func() tea.Msg {
+		defer cancel()
+		return OperationDoneMsg{ID: op.ID, Msg: fn(ctx)}
+	}
+	if s.spinning {
+		return run
+	}
+	s.spinning = true
+	return tea.Batch(run, operationTickCmd())
+}
+
+// CancelOperations aborts every operation in flight and returns how many
+// there were. They are dropped right away; their results still arrive.
+func (s *OperationsState) CancelOperations() int {
+	n := len(s.Operations)
+	for _, op := range s.Operations {
+		op.cancel()
+	}
+	s.Operations = nil
+	return n
+}
+
+// finishOperation drops a returned operation.
+func (s *OperationsState) finishOperation(id int) {
+	for i, op := range s.Operations {
+		if op.ID == id {
+			s.Operations = append(s.Operations[:i], s.Operations[i+1:]...)
+			return
+		}
+	}
+}
+
+// tick advances the spinner while operations are in flight.
+func (s *OperationsState) tick() tea.Cmd {
+	if len(s.Operations) == 0 {
+		s.spinning = false
+		return nil
+	}
+	s.SpinnerFrame++
+	return operationTickCmd()
+}
+
+func operationTickCmd() tea.Cmd {
+	return tea.Tick(operationTickInterval, func(time.Time) tea.Msg {
+		return OperationTickMsg{}
+	})
+}
+
+// cancelledStatus reports how many operations were cancelled.
+func cancelledStatus(n int) string {
+	if n == 1 {
+		return "Cancelled 1 operation"
+	}
+	return fmt.Sprintf("Cancelled %d operations", n)
+}
+
+// cmdCancel aborts the operations in flight and a running log export.
+func cmdCancel(m *Model, _ []string) tea.Cmd {
+	n := m.CancelOperations()
+	if m.LogExport != nil {
+		m.LogExport.Cancel()
+		n++
+	}
+	if n == 0 {
+		m.StatusMessage = "Nothing to cancel"
+		return nil
+	}
+	m.StatusMessage = cancelledStatus(n)
+	return nil
+}
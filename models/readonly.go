@@ -0,0 +1,40 @@
+package models
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// refuseReadOnly tells the user that action is disabled and reports true
+// in read-only mode. Every handler that changes the host, such as start,
+// delete, exec or a future create or prune, checks it first.
+func refuseReadOnly(m *Model, action string) bool {
+	if !m.ReadOnly {
+		return false
+	}
+	m.StatusMessage = readOnlyMessage(action)
+	return true
+}
+
+func readOnlyMessage(action string) string {
+	return "Read-only mode: " + action + " is disabled"
+}
+
+// ReadOnlyServices wraps a backend and refuses every operation that changes
+// the host, so nothing slips through a handler that forgot to check.
+// Everything else is passed on to the wrapped Services.
+type ReadOnlyServices struct {
+	Services
+}
+
+func (ReadOnlyServices) StartContainer(*Model) tea.Cmd   { return refused("start") }
+func (ReadOnlyServices) StopContainer(*Model) tea.Cmd    { return refused("stop") }
+func (ReadOnlyServices) RestartContainer(*Model) tea.Cmd { return refused("restart") }
+func (ReadOnlyServices) DeleteContainer(*Model) tea.Cmd  { return refused("delete") }
+func (ReadOnlyServices) DeleteVolume(*Model) tea.Cmd     { return refused("delete") }
+func (ReadOnlyServices) DeleteImage(*Model) tea.Cmd      { return refused("delete") }
+func (ReadOnlyServices) DeleteNetwork(*Model) tea.Cmd    { return refused("delete") }
+func (ReadOnlyServices) ExecShell(*Model) tea.Cmd        { return refused("exec") }
+
+func refused(action string) tea.Cmd {
+	return func() tea.Msg {
+		return ActionResultMsg{Message: readOnlyMessage(action), Success: false}
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// CompileSearch turns a search query into a regular expression. A query
+// wrapped in slashes ("/err(or)?/") is used as a regex; anything else is
+// matched literally. With smart case, a query containing an upper-case
+// letter is case-sensitive; otherwise matching ignores case.
+func CompileSearch(query string, smartCase bool) (*regexp.Regexp, error) {
+	pattern := regexp.QuoteMeta(query)
+	isRegex := len(query) >= 2 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/")
+	if isRegex {
+		pattern = query[1 : len(query)-1]
+	}
+
+	if !smartCase || !hasUpperCase(query, isRegex) {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return re, nil
+}
+
+// hasUpperCase reports whether query contains an upper-case letter. In a
+// regex, escapes such as \S or \W are not counted.
+func hasUpperCase(query string, isRegex bool) bool {
+	escaped := false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case isRegex && r == '\\':
+			escaped = true
+		case unicode.IsUpper(r):
+			return true
+		}
+	}
+	return false
+}
+
+// applySearchFilter narrows visible to the lines matching the search plus
+// SearchContext lines around each, like grep -C. It records where skipped
+// lines sit between groups in LogContextBreaks.
+func applySearchFilter(m *Model, visible []int) []int {
+	context := max(m.SearchContext, 0)
+	keep := make([]bool, len(visible))
+	for pos, idx := range visible {
+		if !m.SearchRegex.MatchString(m.Logs[idx].Line) {
+			continue
+		}
+		for p := max(pos-context, 0); p <= min(pos+context, len(visible)-1); p++ {
+			keep[p] = true
+		}
+	}
+
+	filtered := make([]int, 0, len(visible))
+	m.LogContextBreaks = make(map[int]bool)
+	last := -1
+	for pos, idx := range visible {
+		if !keep[pos] {
+			continue
+		}
+		if last >= 0 && pos != last+1 {
+			m.LogContextBreaks[len(filtered)] = true
+		}
+		filtered = append(filtered, idx)
+		last = pos
+	}
+	return filtered
+}
@@ -0,0 +1,218 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BulkResultMsg reports an action that was applied to several items at
+// once, with the outcome of each.
+type BulkResultMsg struct {
+	Action   string // Past tense for the summary, e.g. "Stopped"
+	Resource Resource
+	Results  []ItemResult
+}
+
+// ItemResult is the outcome of a bulk action for one item.
+type ItemResult struct {
+	Name string
+	Err  error
+}
+
+// markKey identifies a list item across refreshes. Keys carry the resource
+// kind, so marks made in one list never apply to another. Project rows have
+// no key of their own: marking one marks its containers.
+func markKey(item ListItem) string {
+	switch {
+	case item.IsContainer:
+		return "container:" + item.Container.ID
+	case item.IsVolume:
+		return "volume:" + item.Volume.Name
+	case item.IsImage:
+		return "image:" + item.Image.ID
+	case item.IsNetwork:
+		return "network:" + item.Network.ID
+	}
+	return ""
+}
+
+// IsMarked reports whether item is marked. A project row is marked when
+// all of its containers are.
+func (s *UIState) IsMarked(item ListItem) bool {
+	if item.IsProject {
+		if len(item.Project.Containers) == 0 {
+			return false
+		}
+		for i := range item.Project.Containers {
+			if !s.Marked[markKey(ListItem{IsContainer: true, Container: &item.Project.Containers[i]})] {
+				return false
+			}
+		}
+		return true
+	}
+	key := markKey(item)
+	return key != "" && s.Marked[key]
+}
+
+// setMark marks or unmarks item, or every container of a project row.
+func (s *UIState) setMark(item ListItem, marked bool) {
+	if item.IsProject {
+		for i := range item.Project.Containers {
+			s.setMark(ListItem{IsContainer: true, Container: &item.Project.Containers[i]}, marked)
+		}
+		return
+	}
+	key := markKey(item)
+	switch {
+	case key == "":
+	case marked:
+		if s.Marked == nil {
+			s.Marked = make(map[string]bool)
+		}
+		s.Marked[key] = true
+	default:
+		delete(s.Marked, key)
+	}
+}
+
+// listItems returns every item of the current list, including containers
+// of collapsed projects, or nil for lists that cannot be marked.
+func (m *Model) listItems() []ListItem {
+	var items []ListItem
+	switch m.NavMode {
+	case NavContainers:
+		for i := range m.Containers {
+			items = append(items, ListItem{IsContainer: true, Container: &m.Containers[i]})
+		}
+	case NavVolumes:
+		for i := range m.Volumes {
+			items = append(items, ListItem{IsVolume: true, Volume: &m.Volumes[i]})
+		}
+	case NavImages:
+		for i := range m.Images {
+			items = append(items, ListItem{IsImage: true, Image: &m.Images[i]})
+		}
+	case NavNetworks:
+		for i := range m.Networks {
+			items = append(items, ListItem{IsNetwork: true, Network: &m.Networks[i]})
+		}
+	}
+	return items
+}
+
+// MarkedItems returns the marked items of the current list.
+func (m *Model) MarkedItems() []ListItem {
+	var marked []ListItem
+	for _, item := range m.listItems() {
+		if m.IsMarked(item) {
+			marked = append(marked, item)
+		}
+	}
+	return marked
+}
+
+// ActionTargets returns the items an action applies to: the marked items
+// of the current list, or else the item under the cursor. A compose project
+// row stands for all of its containers.
+func (m *Model) ActionTargets() []ListItem {
+	if marked := m.MarkedItems(); len(marked) > 0 {
+		return marked
+	}
+	item := m.Selected()
+	if item == nil {
+		return nil
+	}
+	if item.IsProject {
+		targets := make([]ListItem, len(item.Project.Containers))
+		for i := range item.Project.Containers {
+			targets[i] = ListItem{IsContainer: true, Container: &item.Project.Containers[i]}
+		}
+		return targets
+	}
+	if markKey(*item) == "" {
+		return nil
+	}
+	return []ListItem{*item}
+}
+
+// clearMarks unmarks every item of the current list, once an action took
+// them over.
+func clearMarks(m *Model) {
+	for _, item := range m.listItems() {
+		m.setMark(item, false)
+	}
+}
+
+// markedStatus reports how many items of the current list are marked.
+func markedStatus(m *Model) {
+	m.StatusMessage = fmt.Sprintf("%d marked", len(m.MarkedItems()))
+}
+
+// handleToggleMark marks or unmarks the row under the cursor and moves on
+// to the next one.
+func handleToggleMark(m *Model) (Model, tea.Cmd) {
+	item := m.Selected()
+	if m.ViewMode != ViewDetails || item == nil || m.listItems() == nil {
+		return *m, nil
+	}
+	m.setMark(*item, !m.IsMarked(*item))
+	if m.Cursor < len(m.Items)-1 {
+		m.Cursor++
+	}
+	markedStatus(m)
+	return *m, nil
+}
+
+// handleMarkAll marks every item of the current list.
+func handleMarkAll(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewDetails || m.listItems() == nil {
+		return *m, nil
+	}
+	for _, item := range m.listItems() {
+		m.setMark(item, true)
+	}
+	markedStatus(m)
+	return *m, nil
+}
+
+// handleInvertMarks marks the unmarked items of the current list and
+// unmarks the others.
+func handleInvertMarks(m *Model) (Model, tea.Cmd) {
+	if m.ViewMode != ViewDetails || m.listItems() == nil {
+		return *m, nil
+	}
+	for _, item := range m.listItems() {
+		m.setMark(item, !m.IsMarked(item))
+	}
+	markedStatus(m)
+	return *m, nil
+}
+
+// bulkFinished shows the outcome of a bulk action and reloads the list it
+// changed. Container events update the container list on their own.
+func bulkFinished(m *Model, msg BulkResultMsg) tea.Cmd {
+	var done, failed []string
+	for _, r := range msg.Results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", r.Name, r.Err))
+		} else {
+			done = append(done, r.Name)
+		}
+	}
+
+	status := fmt.Sprintf("%s %d of %d %s", msg.Action, len(done), len(msg.Results), msg.Resource)
+	if len(done) > 0 {
+		status += ": " + strings.Join(done, ", ")
+	}
+	if len(failed) > 0 {
+		status += "; failed: " + strings.Join(failed, ", ")
+	}
+	m.StatusMessage = status
+
+	if len(done) == 0 || (msg.Resource == ResourceContainers && m.EventStream != nil) {
+		return nil
+	}
+	return reloadResource(m, msg.Resource)
+}
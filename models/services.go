@@ -0,0 +1,76 @@
+package models
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Services is the backend the model runs its commands against. The docker
+// package implements it for a Docker daemon; a fake, a read-only wrapper or
+// another engine can be passed to NewModel instead. Methods receive the
+// model to read the selection and to store streams they open.
+type Services interface {
+	// Lists
+	GroupByProject([]Container) ([]Container, []ComposeGroup)
+	RebuildItems(*Model)
+	RebuildVolumeItems(*Model)
+	RebuildImageItems(*Model)
+	RebuildNetworkItems(*Model)
+	RebuildTopItems(*Model)
+	RebuildAlertItems(*Model)
+	RebuildEventItems(*Model)
+
+	// Loading
+	RefreshContainers(*Model) tea.Cmd
+	LoadContainer(m *Model, id string) tea.Cmd
+	ReloadVolumes(*Model) tea.Cmd
+	ReloadImages(*Model) tea.Cmd
+	ReloadNetworks(*Model) tea.Cmd
+	LoadInspect(*Model) tea.Cmd
+
+	// Actions
+	StartContainer(*Model) tea.Cmd
+	StopContainer(*Model) tea.Cmd
+	RestartContainer(*Model) tea.Cmd
+	DeleteContainer(*Model) tea.Cmd
+	DeleteVolume(*Model) tea.Cmd
+	DeleteImage(*Model) tea.Cmd
+	DeleteNetwork(*Model) tea.Cmd
+	ExecShell(*Model) tea.Cmd
+	OpenPortInBrowser(*Model) tea.Cmd
+
+	// Logs
+	LoadLogs(*Model) tea.Cmd
+	LoadOlderLogs(*Model) tea.Cmd
+	FollowLogs(*Model) tea.Cmd
+	SaveLogs(m *Model, path string, timestamps bool) tea.Cmd
+	ExportLogs(m *Model, path string, timestamps bool) tea.Cmd
+
+	// Stats and alerts
+	LoadStats(*Model) tea.Cmd
+	LoadTopStats(*Model) tea.Cmd
+	LoadProjectStats(*Model) tea.Cmd
+	LoadAlertStats(*Model) tea.Cmd
+	NotifyAlert(*Model, Alert) tea.Cmd
+
+	// Events
+	SubscribeEvents(*Model) tea.Cmd
+	LoadEventHistory(m *Model, since string) tea.Cmd
+
+	// Connect opens a client for another daemon, for :context.
+	Connect(m *Model, c DockerContext) (DockerAPI, error)
+
+	// Quit releases the backend when the program exits.
+	Quit(*Model)
+}
+
+// Renderer draws the model. The ui package implements it.
+type Renderer interface {
+	Frame(*Model) string // Header, panels and status bar around the views
+	Details(m *Model, width, height int) string
+	Logs(m *Model, width, height int) string
+	Ports(m *Model, width, height int) string
+	Env(m *Model, width, height int) string
+	Stats(m *Model, width, height int) string
+	Inspect(m *Model, width, height int) string
+	Top(m *Model, width, height int) string
+	Events(m *Model, width, height int) string
+	HelpScrollMax(*Model) int // Last first line of the help overlay at m's size
+}
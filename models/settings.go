@@ -0,0 +1,63 @@
+package models
+
+import "gdocker/config"
+
+// The settings accessors return the configured section, or its defaults
+// when the model was built without a config, as in tests. They accept a
+// nil model.
+
+// KeySettings returns the key bindings.
+func (m *Model) KeySettings() config.KeyBindings {
+	if m != nil && m.KeyBindings != nil {
+		return *m.KeyBindings
+	}
+	return *config.Default()
+}
+
+// UISettings returns the display settings.
+func (m *Model) UISettings() config.UIConfig {
+	if m != nil && m.UIConfig != nil {
+		return *m.UIConfig
+	}
+	return *config.DefaultUI()
+}
+
+// LogsSettings returns the log viewer settings.
+func (m *Model) LogsSettings() config.LogsConfig {
+	if m != nil && m.LogsConfig != nil {
+		return *m.LogsConfig
+	}
+	return *config.DefaultLogs()
+}
+
+// TimeoutSettings returns the request timeouts.
+func (m *Model) TimeoutSettings() config.TimeoutConfig {
+	if m != nil && m.Timeouts != nil {
+		return *m.Timeouts
+	}
+	return config.DefaultDocker().Timeouts
+}
+
+// TopSettings returns the resource overview settings.
+func (m *Model) TopSettings() config.TopConfig {
+	if m != nil && m.TopConfig != nil {
+		return *m.TopConfig
+	}
+	return *config.DefaultTop()
+}
+
+// AlertMonitorSettings returns the alert monitor settings.
+func (m *Model) AlertMonitorSettings() config.AlertMonitorConfig {
+	if m != nil && m.AlertMonitor != nil {
+		return *m.AlertMonitor
+	}
+	return *config.DefaultAlertMonitor()
+}
+
+// SafetySettings returns the safety policy.
+func (m *Model) SafetySettings() config.SafetyConfig {
+	if m != nil && m.Safety != nil {
+		return *m.Safety
+	}
+	return *config.DefaultSafety()
+}
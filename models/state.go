@@ -1,157 +1,240 @@
 package models
 
 import (
-	"github.com/moby/moby/client"
-)
-
-// Model is the root application state using composition
-type ModelV2 struct {
-	// Core components
-	Docker    *DockerState
-	UI        *UIState
-	Resources *ResourceState
+	"gdocker/config"
+	"regexp"
+	"time"
 
-	// Docker client
-	DockerClient *client.Client
-}
+	tea "github.com/charmbracelet/bubbletea"
+)
 
-// DockerState holds all Docker-related data
+// Model is the root application state. It is composed of one struct per
+// concern; the structs are embedded, so handlers still read m.Containers or
+// m.LogScroll, but each view's state lives next to the sub-model that
+// updates and renders it.
+type Model struct {
+	Services Services // Backend the commands run against
+	Renderer Renderer
+
+	DockerState
+	UIState
+	LogsState
+	StatsState
+	TopState
+	AlertsState
+	EventsState
+	OperationsState
+}
+
+// DockerState holds the client and the resources loaded from the daemon
 type DockerState struct {
-	Containers []Container
-	Standalone []Container
-	Projects   []ComposeGroup
-	Volumes    []Volume
-	Images     []Image
-	Networks   []Network
-}
-
-// UIState holds all UI-related state
+	DockerClient DockerAPI
+	Containers   []Container
+	Standalone   []Container
+	Projects     []ComposeGroup
+	Volumes      []Volume
+	Images       []Image
+	Networks     []Network
+
+	Loading          map[Resource]bool   // Lists still waiting for their first load
+	LoadErrors       map[Resource]string // Last failure of each list, shown in its place
+	Disconnected     bool                // The daemon is unreachable
+	Reconnecting     bool                // A reconnect attempt is in flight
+	ReconnectBackoff time.Duration       // Delay before the next reconnect attempt
+	Timeouts         *config.TimeoutConfig
+	ReadOnly         bool            // Operations that change the host are disabled
+	Contexts         []DockerContext // Daemons :context can switch to
+	Context          string          // Name of the active context
+	EnvCache         *EnvCache       // Container environments read from this daemon
+}
+
+// UIState holds the layout, the sidebar list and the keyboard input state
 type UIState struct {
-	Navigation NavigationState
-	View       ViewState
-	Dimensions DimensionState
-	List       ListState
-	Message    string
+	KeyBindings     *config.KeyBindings
+	UIConfig        *config.UIConfig
+	Items           []ListItem
+	Cursor          int
+	Width           int
+	Height          int
+	NavMode         NavigationMode
+	ViewMode        ViewMode
+	Input           InputMode
+	CommandInput    string // Current command input
+	StatusMessage   string
+	AutoRefreshSecs int
+	SelectedPort    int             // For port selection to open in browser
+	InspectData     string          // JSON inspect data
+	Marked          map[string]bool // Marked list items, see markKey
+	Safety          *config.SafetyConfig
+	Confirm         *Confirmation // Open confirmation dialog, if any
+	HelpScroll      int           // First line shown in the help overlay
+}
+
+// Selected returns the list item under the cursor, or nil for an empty list.
+func (s *UIState) Selected() *ListItem {
+	if s.Cursor < 0 || s.Cursor >= len(s.Items) {
+		return nil
+	}
+	return &s.Items[s.Cursor]
 }
 
-// NavigationState tracks which resource type we're viewing
-type NavigationState struct {
-	Mode  NavigationMode
-	Items []ListItem
+// SelectedContainer returns the container under the cursor, if any.
+func (s *UIState) SelectedContainer() *Container {
+	if item := s.Selected(); item != nil && item.IsContainer {
+		return item.Container
+	}
+	return nil
 }
 
-// ViewState tracks the current view and its specific state
-type ViewState struct {
-	Mode         ViewMode
-	Logs         *LogsState
-	Ports        *PortsState
-	Stats        *ContainerStats
-	Inspect      *InspectState
-	VolumeBrowse *VolumeState
-}
+// InputMode is what keys typed into the model go to. Only one prompt or
+// overlay can be open at a time.
+type InputMode int
 
-// LogsState holds log viewing state
-type LogsState struct {
-	Lines     []string
-	ScrollPos int
-	Following bool
-	Search    *SearchState
-}
+const (
+	InputNormal  InputMode = iota // Keys run the bound handlers
+	InputCommand                  // Typing a : command
+	InputSearch                   // Typing a ? log search
+	InputHelp                     // The help overlay is open
+	InputConfirm                  // A confirmation dialog is open
+)
 
-// SearchState holds search-specific state
+// LogsState holds the log view: the buffer, its filters and the live stream
+type LogsState struct {
+	LogsConfig      *config.LogsConfig
+	Logs            []LogEntry
+	VisibleLogs     []int           // Indices into Logs that pass the active filters
+	LogScroll       int             // Position within VisibleLogs
+	LogStream       *LogStream      // Live log subscription while following
+	FollowingLogs   bool            // Whether logs are being followed
+	LogTargets      []LogTarget     // Containers feeding the log view
+	LogProject      string          // Compose project when showing merged project logs
+	HiddenSources   map[string]bool // Log sources hidden in the project log view
+	LogStreamFilter StreamFilter    // Which output streams the log view shows
+	LogRangeSince   string          // Docker "since" bound set with :logs since
+	LogRangeUntil   string          // Docker "until" bound set with :logs until
+	LoadingOlder    bool            // An older page of logs is being fetched
+	LogsAtStart     bool            // No older lines are left to page in
+	LogJSONView     bool            // Render JSON lines as columns of chosen fields
+	LogDetail       bool            // Expand the selected line into an indented tree
+	LogFieldFilters []FieldFilter   // JSON field filters set with :filter
+	LogMinLevel     LogLevel        // Hide classified lines below this level
+	LevelClassifier *LevelClassifier
+	LogExport       *LogExport // Running :w! export, nil when idle
+	LogGeneration   int        // Bumped for every newly loaded buffer; older pages carry it
+
+	SearchState
+}
+
+// SearchState holds the log search
 type SearchState struct {
-	Active      bool
-	Query       string
-	Results     []int
-	ResultIndex int
-}
-
-// PortsState holds port viewing state
-type PortsState struct {
-	Selected int
-}
-
-// InspectState holds inspect viewing state
-type InspectState struct {
-	Data      string
-	ScrollPos int
-}
-
-// VolumeState holds volume browsing state
-type VolumeState struct {
-	Files []string
-	Path  string
-}
-
-// DimensionState holds terminal dimensions
-type DimensionState struct {
-	Width  int
-	Height int
-}
-
-// ListState holds list navigation state
-type ListState struct {
-	Cursor int
-}
-
-// ResourceState provides access to current resource
-type ResourceState struct {
-	docker *DockerState
-	nav    *NavigationState
-}
-
-// GetCurrentContainer returns the currently selected container, if any
-func (r *ResourceState) GetCurrentContainer() *Container {
-	if r.nav.Mode != NavContainers {
-		return nil
+	SearchQuery      string         // Current search query
+	SearchResults    []int          // VisibleLogs positions that match the search
+	SearchResultIdx  int            // Current position in SearchResults
+	SearchRegex      *regexp.Regexp // Compiled SearchQuery, nil without a valid query
+	SearchSmartCase  bool           // Upper-case letters in the query make it case-sensitive
+	SearchFilter     bool           // Show only matching lines and their context
+	SearchContext    int            // Lines of context around each match in filter mode
+	LogContextBreaks map[int]bool   // Filter mode: VisibleLogs positions that follow skipped lines
+}
+
+// StatsState holds the stats view and the project stats panel
+type StatsState struct {
+	Stats            *ContainerStats  // Latest sample in the stats view
+	StatsHistory     []ContainerStats // Rolling samples, oldest first
+	StatsStream      *StatsStream
+	ProjectStatsName string                    // Project the panel samples belong to
+	ProjectStats     map[string]ContainerStats // Project panel samples by container ID
+}
+
+// TopState holds the resource usage overview
+type TopState struct {
+	TopConfig     *config.TopConfig
+	TopStats      map[string]ContainerStats // Overview samples by container ID
+	TopSort       TopColumn
+	TopSortAsc    bool
+	TopGeneration int  // Bumped on entering the overview; stale rounds are dropped
+	TopLoading    bool // First round of overview stats is in flight
+}
+
+// AlertsState holds the alert rules and what the monitor has seen
+type AlertsState struct {
+	AlertMonitor *config.AlertMonitorConfig
+	AlertRules   []config.AlertRule
+	Alerts       []Alert                   // Fired alerts, oldest first
+	AlertPending map[string]time.Time      // Breaches waiting out their rule's For duration
+	AlertStats   map[string]ContainerStats // Monitor samples by container ID
+}
+
+// EventsState holds the daemon event subscription and the timeline
+type EventsState struct {
+	EventStream   *EventStream  // Daemon events; nil while polling
+	EventBackoff  time.Duration // Delay before the next reconnect attempt
+	LastEventTime time.Time     // Daemon time of the newest streamed event; a new stream resumes after it
+	EventLog      []DockerEvent // Timeline, oldest first
+	EventFilter   EventFilter
+	EventSince    string // History range loaded with :events since
+	EventLoading  bool
+}
+
+// ViewModel is the behaviour of one view. Update is offered messages before
+// the shared handling in Model.Update and reports whether it consumed msg;
+// View renders the view into the given area.
+type ViewModel interface {
+	Update(m *Model, msg tea.Msg) (tea.Cmd, bool)
+	View(m *Model, width, height int) string
+}
+
+// subModels are offered every message in this order. Views without
+// messages of their own, like ports or env, only render.
+var subModels = []ViewModel{logsView{}, statsView{}, topView{}, alertsView{}, eventsView{}}
+
+// ActiveView returns the sub-model that renders the main area: the right
+// panel, or the full width for the overview and the events timeline.
+func (m *Model) ActiveView() ViewModel {
+	switch m.ViewMode {
+	case ViewLogs:
+		return logsView{}
+	case ViewPorts:
+		return portsView{}
+	case ViewEnv:
+		return envView{}
+	case ViewStats:
+		return statsView{}
+	case ViewInspect:
+		return inspectView{}
 	}
-
-	for _, item := range r.nav.Items {
-		if item.IsContainer {
-			return item.Container
-		}
+	switch m.NavMode {
+	case NavTop:
+		return topView{}
+	case NavEvents:
+		return eventsView{}
 	}
-	return nil
+	return detailsView{}
 }
 
-// GetCurrentVolume returns the currently selected volume, if any
-func (r *ResourceState) GetCurrentVolume() *Volume {
-	if r.nav.Mode != NavVolumes {
-		return nil
-	}
-
-	for _, item := range r.nav.Items {
-		if item.IsVolume {
-			return item.Volume
-		}
-	}
-	return nil
+// FullWidth reports whether the active view replaces the sidebar list.
+func (m *Model) FullWidth() bool {
+	return m.ViewMode == ViewDetails && (m.NavMode == NavTop || m.NavMode == NavEvents)
 }
 
-// GetCurrentImage returns the currently selected image, if any
-func (r *ResourceState) GetCurrentImage() *Image {
-	if r.nav.Mode != NavImages {
-		return nil
-	}
-
-	for _, item := range r.nav.Items {
-		if item.IsImage {
-			return item.Image
-		}
-	}
-	return nil
-}
+// Views that only render; their keys are handled by the shared key map.
+type (
+	detailsView struct{}
+	portsView   struct{}
+	envView     struct{}
+	inspectView struct{}
+)
 
-// GetCurrentNetwork returns the currently selected network, if any
-func (r *ResourceState) GetCurrentNetwork() *Network {
-	if r.nav.Mode != NavNetworks {
-		return nil
-	}
+func (detailsView) Update(*Model, tea.Msg) (tea.Cmd, bool) { return nil, false }
+func (portsView) Update(*Model, tea.Msg) (tea.Cmd, bool)   { return nil, false }
+func (envView) Update(*Model, tea.Msg) (tea.Cmd, bool)     { return nil, false }
+func (inspectView) Update(*Model, tea.Msg) (tea.Cmd, bool) { return nil, false }
 
-	for _, item := range r.nav.Items {
-		if item.IsNetwork {
-			return item.Network
-		}
-	}
-	return nil
+func (detailsView) View(m *Model, width, height int) string {
+	return m.Renderer.Details(m, width, height)
+}
+func (portsView) View(m *Model, width, height int) string { return m.Renderer.Ports(m, width, height) }
+func (envView) View(m *Model, width, height int) string   { return m.Renderer.Env(m, width, height) }
+func (inspectView) View(m *Model, width, height int) string {
+	return m.Renderer.Inspect(m, width, height)
 }
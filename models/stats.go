@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxStatsHistory is how many samples the stats view keeps, about five
+// minutes at Docker's one-second stats interval.
+const maxStatsHistory = 300
+
+// Next waits for the next sample on the stream.
+func (s *StatsStream) Next() tea.Cmd {
+	return func() tea.Msg {
+		sample, ok := <-s.Samples
+		if !ok {
+			return StatsStreamEndedMsg{Stream: s, Err: <-s.Done}
+		}
+		return StatsSampleMsg{Stream: s, Stats: sample}
+	}
+}
+
+// statsView is the sub-model of the stats view and of the stats panel in a
+// project's details.
+type statsView struct{}
+
+func (statsView) View(m *Model, width, height int) string {
+	return m.Renderer.Stats(m, width, height)
+}
+
+func (statsView) Update(m *Model, msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case StatsSampleMsg:
+		// Drop samples from a stream that was cancelled in the meantime.
+		if msg.Stream != m.StatsStream {
+			return nil, true
+		}
+		appendStatsSample(m, msg.Stats)
+		return msg.Stream.Next(), true
+
+	case StatsStreamEndedMsg:
+		if msg.Stream != m.StatsStream {
+			return nil, true
+		}
+		m.StatsStream = nil
+		if msg.Err != nil {
+			m.StatusMessage = fmt.Sprintf("Stats stream stopped: %v", msg.Err)
+		} else {
+			m.StatusMessage = "Stats stream closed"
+		}
+		return nil, true
+
+	case ProjectStatsTickMsg:
+		// The panel loop runs for the whole session but only samples while a
+		// project's details are on screen.
+		if project := selectedStatsProject(m); project != nil {
+			return m.Services.LoadProjectStats(m), true
+		}
+		return projectStatsTickCmd(m.TopSettings().RefreshSeconds), true
+
+	case ProjectStatsLoadedMsg:
+		if project := selectedStatsProject(m); project != nil && project.Name == msg.Project {
+			m.ProjectStatsName = msg.Project
+			m.ProjectStats = msg.Stats
+		}
+		return projectStatsTickCmd(m.TopSettings().RefreshSeconds), true
+	}
+	return nil, false
+}
+
+// reset clears the stats view on leaving it.
+func (s *StatsState) reset() {
+	if s.StatsStream != nil {
+		s.StatsStream.Cancel()
+		s.StatsStream = nil
+	}
+	s.Stats = nil
+	s.StatsHistory = nil
+}
+
+// stopStatsStream cancels the active stats subscription, if any.
+func stopStatsStream(m *Model) {
+	if m.StatsStream != nil {
+		m.StatsStream.Cancel()
+		m.StatsStream = nil
+	}
+}
+
+// appendStatsSample records a sample as the latest stats and in the history.
+func appendStatsSample(m *Model, sample ContainerStats) {
+	m.StatsHistory = append(m.StatsHistory, sample)
+	if len(m.StatsHistory) > maxStatsHistory {
+		m.StatsHistory = m.StatsHistory[len(m.StatsHistory)-maxStatsHistory:]
+	}
+	m.Stats = &m.StatsHistory[len(m.StatsHistory)-1]
+}
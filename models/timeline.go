@@ -0,0 +1,116 @@
+package models
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxEventLog caps how many events the timeline keeps.
+const maxEventLog = 2000
+
+// eventTypeCycle is the order the type filter key steps through.
+var eventTypeCycle = []string{"", "container", "image", "volume", "network"}
+
+// EventFilter narrows the events timeline. Empty fields match everything.
+type EventFilter struct {
+	Types     []string // Event types, e.g. container or network
+	Actions   []string // Action prefixes, e.g. die or health_status
+	Container string   // Glob matched against container names
+	Project   string   // Compose project
+}
+
+// Empty reports whether the filter lets every event through.
+func (f EventFilter) Empty() bool {
+	return len(f.Types) == 0 && len(f.Actions) == 0 && f.Container == "" && f.Project == ""
+}
+
+// String describes the active filter terms for the timeline header.
+func (f EventFilter) String() string {
+	var terms []string
+	if len(f.Types) > 0 {
+		terms = append(terms, "type="+strings.Join(f.Types, ","))
+	}
+	if len(f.Actions) > 0 {
+		terms = append(terms, "action="+strings.Join(f.Actions, ","))
+	}
+	if f.Container != "" {
+		terms = append(terms, "container="+f.Container)
+	}
+	if f.Project != "" {
+		terms = append(terms, "project="+f.Project)
+	}
+	return strings.Join(terms, " ")
+}
+
+// EventHistoryLoadedMsg delivers the events replayed for :events since.
+// Until is when the replay was requested; live events after it are kept.
+type EventHistoryLoadedMsg struct {
+	Since  string
+	Until  time.Time
+	Events []DockerEvent
+	Err    error
+}
+
+// EventMatches reports whether e passes the timeline filter.
+func EventMatches(m *Model, e DockerEvent) bool {
+	f := m.EventFilter
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+	if len(f.Actions) > 0 && !slices.ContainsFunc(f.Actions, func(a string) bool {
+		return strings.HasPrefix(e.Action, a)
+	}) {
+		return false
+	}
+	if f.Project != "" && e.Attributes["com.docker.compose.project"] != f.Project {
+		return false
+	}
+	if f.Container != "" {
+		ok, _ := path.Match(f.Container, eventContainerName(m, e))
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// eventContainerName is the container an event concerns: the actor of a
+// container event, or the container a network was connected to.
+func eventContainerName(m *Model, e DockerEvent) string {
+	switch {
+	case e.Type == "container":
+		return e.Name()
+	case e.Attributes["container"] != "":
+		id := shortID(e.Attributes["container"])
+		for _, c := range m.Containers {
+			if c.ID == id {
+				return c.Name
+			}
+		}
+	}
+	return ""
+}
+
+// appendEventLog adds live events to the timeline, dropping the oldest
+// past the cap.
+func appendEventLog(m *Model, events []DockerEvent) {
+	m.EventLog = append(m.EventLog, events...)
+	if len(m.EventLog) > maxEventLog {
+		m.EventLog = m.EventLog[len(m.EventLog)-maxEventLog:]
+	}
+}
+
+// mergeEventHistory replaces the timeline with replayed history, keeping
+// the live events that arrived after the replay was requested.
+func mergeEventHistory(m *Model, msg EventHistoryLoadedMsg) {
+	events := msg.Events
+	for _, e := range m.EventLog {
+		if e.Time.After(msg.Until) {
+			events = append(events, e)
+		}
+	}
+	m.EventLog = nil
+	appendEventLog(m, events)
+}
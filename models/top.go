@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TopColumn is a sortable column of the resource usage overview.
+type TopColumn int
+
+const (
+	TopColumnName TopColumn = iota
+	TopColumnCPU
+	TopColumnMem
+	TopColumnNetRx
+	TopColumnNetTx
+	TopColumnBlock
+	TopColumnPIDs
+	topColumnCount
+)
+
+var topColumnNames = []string{"name", "cpu", "mem", "rx", "tx", "block", "pids"}
+
+func (c TopColumn) String() string {
+	if c >= 0 && c < topColumnCount {
+		return topColumnNames[c]
+	}
+	return "unknown"
+}
+
+// ParseTopColumn looks up a column by the name shown in :sort.
+func ParseTopColumn(name string) (TopColumn, bool) {
+	for i, n := range topColumnNames {
+		if strings.EqualFold(n, name) {
+			return TopColumn(i), true
+		}
+	}
+	return 0, false
+}
+
+// TopSortValue is the numeric value a column sorts by. Rates are used for
+// network and block I/O so the busiest containers come first.
+func TopSortValue(stats ContainerStats, column TopColumn) float64 {
+	switch column {
+	case TopColumnCPU:
+		return stats.CPUPercent
+	case TopColumnMem:
+		return float64(stats.MemUsage)
+	case TopColumnNetRx:
+		return stats.NetRxRate
+	case TopColumnNetTx:
+		return stats.NetTxRate
+	case TopColumnBlock:
+		return stats.BlockReadRate + stats.BlockWriteRate
+	case TopColumnPIDs:
+		return float64(stats.PIDs)
+	default:
+		return 0
+	}
+}
+
+// topView is the sub-model of the resource usage overview.
+type topView struct{}
+
+func (topView) View(m *Model, width, height int) string {
+	return m.Renderer.Top(m, width, height)
+}
+
+func (topView) Update(m *Model, msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case TopStatsLoadedMsg:
+		// A round from before leaving the overview ends its refresh loop.
+		if msg.Generation != m.TopGeneration || m.NavMode != NavTop {
+			return nil, true
+		}
+		m.TopStats = msg.Stats
+		m.TopLoading = false
+		if msg.Failed > 0 {
+			m.StatusMessage = fmt.Sprintf("Stats unavailable for %d containers", msg.Failed)
+		}
+		m.Services.RebuildTopItems(m)
+		return topTickCmd(m.TopSettings().RefreshSeconds, m.TopGeneration), true
+
+	case TopTickMsg:
+		if msg.Generation != m.TopGeneration || m.NavMode != NavTop {
+			return nil, true
+		}
+		return m.Services.LoadTopStats(m), true
+	}
+	return nil, false
+}
+
+// topTickCmd schedules the next round of overview stats for generation.
+func topTickCmd(seconds, generation int) tea.Cmd {
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return TopTickMsg{Generation: generation}
+	})
+}
+
+// projectStatsTickCmd schedules the next check of the project stats panel.
+func projectStatsTickCmd(seconds int) tea.Cmd {
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return ProjectStatsTickMsg{}
+	})
+}
+
+// selectedStatsProject returns the project whose details are shown, or nil
+// when no project panel is visible or the project has nothing running.
+func selectedStatsProject(m *Model) *ComposeGroup {
+	if m.NavMode != NavContainers || m.ViewMode != ViewDetails || m.Cursor >= len(m.Items) {
+		return nil
+	}
+	item := m.Items[m.Cursor]
+	if !item.IsProject {
+		return nil
+	}
+	for _, c := range item.Project.Containers {
+		if c.State == "running" {
+			return item.Project
+		}
+	}
+	return nil
+}
@@ -2,54 +2,62 @@ package models
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// These will be set by the docker and ui packages to avoid circular imports
-var (
-	GroupByProjectFunc      func([]Container) ([]Container, []ComposeGroup)
-	RebuildItemsFunc        func(*Model)
-	RebuildVolumeItemsFunc  func(*Model)
-	RebuildImageItemsFunc   func(*Model)
-	RebuildNetworkItemsFunc func(*Model)
-	RefreshContainersFunc   func(*Model) tea.Cmd
-	StartContainerFunc      func(*Model) tea.Cmd
-	StopContainerFunc       func(*Model) tea.Cmd
-	RestartContainerFunc    func(*Model) tea.Cmd
-	DeleteContainerFunc     func(*Model) tea.Cmd
-	DeleteVolumeFunc        func(*Model) tea.Cmd
-	DeleteImageFunc         func(*Model) tea.Cmd
-	LoadLogsFunc            func(*Model) tea.Cmd
-	FollowLogsFunc          func(*Model) tea.Cmd
-	LoadInspectFunc         func(*Model) tea.Cmd
-	LoadStatsFunc           func(*Model) tea.Cmd
-	ExecShellFunc           func(*Model) tea.Cmd
-	OpenPortInBrowserFunc   func(*Model) tea.Cmd
-	QuitFunc                func(*Model)
-	RenderViewFunc          func(*Model) string
-)
-
 func (m Model) Init() tea.Cmd {
-	return autoRefreshTickCmd(m.AutoRefreshSecs)
+	cmds := []tea.Cmd{autoRefreshTickCmd(m.AutoRefreshSecs), projectStatsTickCmd(m.TopSettings().RefreshSeconds)}
+	// The lists load concurrently once the program is running
+	if len(m.Loading) > 0 {
+		cmds = append(cmds, resyncAll(&m))
+	}
+	if len(m.AlertRules) > 0 {
+		cmds = append(cmds, m.Services.LoadAlertStats(&m))
+	}
+	if m.EventStream != nil {
+		cmds = append(cmds, m.EventStream.Next())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Command and help input come before any view sees the key.
+	if key, ok := msg.(tea.KeyMsg); ok && m.Input != InputNormal && m.Input != InputSearch {
+		cmd := updateInput(&m, key)
+		return m, cmd
+	}
+	// A finished operation delivers its result as if it had been sent alone.
+	if done, ok := msg.(OperationDoneMsg); ok {
+		m.finishOperation(done.ID)
+		if done.Msg == nil {
+			return m, nil
+		}
+		return m.Update(done.Msg)
+	}
+	for _, view := range subModels {
+		if cmd, ok := view.Update(&m, msg); ok {
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case AutoRefreshTickMsg:
 		next := autoRefreshTickCmd(m.AutoRefreshSecs)
-		if m.NavMode == NavContainers {
-			return m, tea.Batch(next, RefreshContainersFunc(&m))
+		// Events keep the lists current; polling only covers a dropped stream.
+		// While the daemon is unreachable the reconnect loop polls instead.
+		if m.EventStream == nil && !m.Disconnected && (m.NavMode == NavContainers || m.NavMode == NavTop) {
+			return m, tea.Batch(next, m.Services.RefreshContainers(&m))
 		}
 		return m, next
 
-	case LogFollowTickMsg:
-		if m.ViewMode == ViewLogs && m.FollowingLogs {
-			return m, tea.Batch(logFollowTickCmd(), FollowLogsFunc(&m))
-		}
-		return m, nil
+	case OperationTickMsg:
+		cmd := m.tick()
+		return m, cmd
 
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
@@ -57,91 +65,95 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case ContainersRefreshedMsg:
-		// Save expanded state
-		expandedProjects := make(map[string]bool)
-		for _, p := range m.Projects {
-			if p.Expanded {
-				expandedProjects[p.Name] = true
-			}
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
 		}
+		setContainers(&m, msg.Containers)
+		cmd := resourceLoaded(&m, ResourceContainers)
+		return m, cmd
 
-		// Refresh containers
-		m.Containers = msg.Containers
-		m.Standalone, m.Projects = GroupByProjectFunc(msg.Containers)
-
-		// Restore expanded state
-		for i := range m.Projects {
-			if expandedProjects[m.Projects[i].Name] {
-				m.Projects[i].Expanded = true
-			}
+	case ResourceFailedMsg:
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
 		}
+		cmd := resourceFailed(&m, msg)
+		return m, cmd
 
-		RebuildItemsFunc(&m)
-		return m, nil
+	case DaemonReconnectMsg:
+		cmd := reconnect(&m)
+		return m, cmd
 
-	case LogsLoadedMsg:
-		m.Logs = msg.Lines
-		m.LogScroll = len(msg.Lines) - 1 // Scroll to bottom
-		m.LogSince = msg.Since
-		m.ViewMode = ViewLogs
-		m.FollowingLogs = msg.Follow
-		if m.FollowingLogs {
-			return m, logFollowTickCmd()
+	case ContainerUpdatedMsg:
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
 		}
-		return m, nil
-
-	case LogsFollowedMsg:
-		if len(msg.Lines) > 0 {
-			for _, line := range msg.Lines {
-				// Skip immediate duplicates caused by coarse timestamp "since" filters.
-				if len(m.Logs) == 0 || m.Logs[len(m.Logs)-1] != line {
-					m.Logs = append(m.Logs, line)
-				}
-			}
-			m.LogScroll = len(m.Logs) - 1
-			if m.SearchQuery != "" {
-				performSearch(&m)
-				if len(m.SearchResults) > 0 {
-					m.LogScroll = len(m.Logs) - 1
-				}
-			}
-		}
-		if !msg.Since.IsZero() {
-			m.LogSince = msg.Since
-		}
-		return m, nil
-
-	case StatsLoadedMsg:
-		m.Stats = msg.Stats
-		m.ViewMode = ViewStats
+		updateContainer(&m, msg.ID, msg.Container)
 		return m, nil
 
 	case ActionResultMsg:
 		m.StatusMessage = msg.Message
 		if msg.Success {
-			// Refresh based on navigation mode
+			// Refresh based on navigation mode. With events connected the
+			// container list updates itself.
 			switch m.NavMode {
 			case NavContainers:
-				return m, RefreshContainersFunc(&m)
+				if m.EventStream != nil {
+					return m, nil
+				}
+				return m, m.Services.RefreshContainers(&m)
 			case NavVolumes:
-				RebuildVolumeItemsFunc(&m)
+				m.Services.RebuildVolumeItems(&m)
 			case NavImages:
-				RebuildImageItemsFunc(&m)
+				m.Services.RebuildImageItems(&m)
 			}
 		}
 		return m, nil
 
+	case BulkResultMsg:
+		cmd := bulkFinished(&m, msg)
+		return m, cmd
+
 	case VolumesLoadedMsg:
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
+		}
 		m.Volumes = msg.Volumes
-		RebuildVolumeItemsFunc(&m)
-		m.StatusMessage = "Volume deleted"
-		return m, nil
+		if m.NavMode == NavVolumes {
+			m.Services.RebuildVolumeItems(&m)
+		}
+		if msg.Message != "" {
+			m.StatusMessage = msg.Message
+		}
+		cmd := resourceLoaded(&m, ResourceVolumes)
+		return m, cmd
 
 	case ImagesLoadedMsg:
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
+		}
 		m.Images = msg.Images
-		RebuildImageItemsFunc(&m)
-		m.StatusMessage = "Image deleted"
-		return m, nil
+		if m.NavMode == NavImages {
+			m.Services.RebuildImageItems(&m)
+		}
+		if msg.Message != "" {
+			m.StatusMessage = msg.Message
+		}
+		cmd := resourceLoaded(&m, ResourceImages)
+		return m, cmd
+
+	case NetworksLoadedMsg:
+		if fromOtherDaemon(&m, msg.Client) {
+			return m, nil
+		}
+		m.Networks = msg.Networks
+		if m.NavMode == NavNetworks {
+			m.Services.RebuildNetworkItems(&m)
+		}
+		if msg.Message != "" {
+			m.StatusMessage = msg.Message
+		}
+		cmd := resourceLoaded(&m, ResourceNetworks)
+		return m, cmd
 
 	case InspectLoadedMsg:
 		m.InspectData = msg.Data
@@ -150,61 +162,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		// Handle command mode input
-		if m.CommandMode {
-			switch msg.String() {
-			case "enter":
-				// Execute command
-				m.CommandMode = false
-				return m, executeCommand(&m)
-			case "esc":
-				// Cancel command
-				m.CommandMode = false
-				m.CommandInput = ""
-				m.StatusMessage = ""
-				return m, nil
-			case "backspace":
-				if len(m.CommandInput) > 0 {
-					m.CommandInput = m.CommandInput[:len(m.CommandInput)-1]
-				}
-				return m, nil
-			default:
-				// Add character to command input
-				if len(msg.String()) == 1 {
-					m.CommandInput += msg.String()
-				}
-				return m, nil
-			}
-		}
-
-		// Handle search mode input
-		if m.SearchMode {
-			switch msg.String() {
-			case "enter":
-				// Execute search
-				m.SearchMode = false
-				performSearch(&m)
-				return m, nil
-			case "esc":
-				// Cancel search
-				m.SearchMode = false
-				m.SearchQuery = ""
-				m.StatusMessage = ""
-				return m, nil
-			case "backspace":
-				if len(m.SearchQuery) > 0 {
-					m.SearchQuery = m.SearchQuery[:len(m.SearchQuery)-1]
-				}
-				return m, nil
-			default:
-				// Add character to search query
-				if len(msg.String()) == 1 {
-					m.SearchQuery += msg.String()
-				}
-				return m, nil
-			}
-		}
-
 		// Build key handler map
 		keyHandlers := buildKeyHandlerMap(&m)
 
@@ -219,34 +176,152 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
-	return RenderViewFunc(&m)
+	return m.Renderer.Frame(&m)
+}
+
+// updateInput handles a key while the command prompt, the help overlay or
+// a confirmation dialog is open. The log search prompt belongs to the log
+// view.
+func updateInput(m *Model, msg tea.KeyMsg) tea.Cmd {
+	if m.Input == InputConfirm {
+		return updateConfirm(m, msg.String())
+	}
+	if m.Input == InputHelp {
+		// Only scrolling, closing help or starting a command get through
+		// the overlay
+		key := msg.String()
+		nav := m.KeyBindings.Navigation
+		switch {
+		case slices.Contains(nav.Down, key):
+			m.HelpScroll = min(m.HelpScroll+1, helpScrollMax(m))
+		case slices.Contains(nav.Up, key):
+			m.HelpScroll = max(m.HelpScroll-1, 0)
+		case slices.Contains(nav.Top, key):
+			m.HelpScroll = 0
+		case slices.Contains(nav.Bottom, key):
+			m.HelpScroll = helpScrollMax(m)
+		}
+		if slices.Contains(m.KeyBindings.Views.Back, key) {
+			m.Input = InputNormal
+			m.StatusMessage = ""
+		} else if slices.Contains(m.KeyBindings.Commands.Enter, key) {
+			m.Input = InputCommand
+			m.CommandInput = ""
+		} else if slices.Contains(m.KeyBindings.General.ForceQuit, key) {
+			_, cmd := handleForceQuit(m)
+			return cmd
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		// Execute command
+		m.Input = InputNormal
+		return executeCommand(m)
+	case "esc":
+		// Cancel command
+		m.Input = InputNormal
+		m.CommandInput = ""
+		m.StatusMessage = ""
+	case "backspace":
+		if len(m.CommandInput) > 0 {
+			m.CommandInput = m.CommandInput[:len(m.CommandInput)-1]
+		}
+	default:
+		// Add character to command input
+		if len(msg.String()) == 1 {
+			m.CommandInput += msg.String()
+		}
+	}
+	return nil
 }
 
 func performSearch(m *Model) {
-	if m.SearchQuery == "" {
-		m.SearchResults = nil
-		m.SearchResultIdx = 0
+	if !findMatches(m) {
 		return
 	}
+	if len(m.SearchResults) > 0 {
+		m.LogScroll = m.SearchResults[0]
+		m.StatusMessage = formatSearchStatus(m)
+	} else {
+		m.StatusMessage = "No matches found"
+	}
+}
 
-	var results []int
-	query := strings.ToLower(m.SearchQuery)
+// refreshSearch reruns the search after lines were added or dropped
+// without moving the view. The selected match stays on the log line at
+// index line, or moves to the next match when that line is gone.
+func refreshSearch(m *Model, line int) {
+	if !findMatches(m) || len(m.SearchResults) == 0 {
+		return
+	}
+	idx := sort.Search(len(m.SearchResults), func(i int) bool {
+		return m.VisibleLogs[m.SearchResults[i]] >= line
+	})
+	m.SearchResultIdx = min(idx, len(m.SearchResults)-1)
+	m.StatusMessage = formatSearchStatus(m)
+}
 
-	for i, line := range m.Logs {
-		if strings.Contains(strings.ToLower(line), query) {
-			results = append(results, i)
-		}
+// currentMatchLine is the index in m.Logs of the selected search match,
+// or -1 when there is none.
+func currentMatchLine(m *Model) int {
+	if m.SearchResultIdx >= len(m.SearchResults) {
+		return -1
+	}
+	if pos := m.SearchResults[m.SearchResultIdx]; pos < len(m.VisibleLogs) {
+		return m.VisibleLogs[pos]
 	}
+	return -1
+}
 
-	m.SearchResults = results
+// findMatches compiles the search and collects the positions of the
+// visible lines it matches. It reports false when there is no search.
+func findMatches(m *Model) bool {
+	m.SearchResults = nil
 	m.SearchResultIdx = 0
+	if m.SearchQuery == "" {
+		clearSearchRegex(m)
+		return false
+	}
 
-	if len(results) > 0 {
-		m.LogScroll = results[0]
-		m.StatusMessage = formatSearchStatus(m)
-	} else {
-		m.StatusMessage = "No matches found"
+	re, err := CompileSearch(m.SearchQuery, m.SearchSmartCase)
+	if err != nil {
+		clearSearchRegex(m)
+		m.StatusMessage = err.Error()
+		return false
+	}
+	m.SearchRegex = re
+	if m.SearchFilter {
+		rebuildVisibleLogs(m)
 	}
+
+	for pos, idx := range m.VisibleLogs {
+		if re.MatchString(m.Logs[idx].Line) {
+			m.SearchResults = append(m.SearchResults, pos)
+		}
+	}
+	return true
+}
+
+// clearSearchRegex drops the compiled search, restoring lines hidden by
+// filter mode.
+func clearSearchRegex(m *Model) {
+	if m.SearchRegex == nil {
+		return
+	}
+	m.SearchRegex = nil
+	if m.SearchFilter {
+		rebuildVisibleLogs(m)
+	}
+}
+
+// helpScrollMax is how far the help overlay scrolls at the current size.
+func helpScrollMax(m *Model) int {
+	if m.Renderer == nil {
+		return 0
+	}
+	return m.Renderer.HelpScrollMax(m)
 }
 
 func formatSearchStatus(m *Model) string {
@@ -256,16 +331,32 @@ func formatSearchStatus(m *Model) string {
 	return fmt.Sprintf("Match %d/%d", m.SearchResultIdx+1, len(m.SearchResults))
 }
 
+// rawArgCommands receive everything after their name as one argument.
+var rawArgCommands = map[string]bool{"w": true, "w!": true}
+
 func executeCommand(m *Model) tea.Cmd {
 	cmd := strings.TrimSpace(m.CommandInput)
 	m.CommandInput = ""
 
+	// Split "name arg1 arg2" into the command name and its arguments
+	name, args := cmd, []string(nil)
+	if fields := strings.Fields(cmd); len(fields) > 0 {
+		name, args = fields[0], fields[1:]
+	}
+	// Commands taking a path get the rest of the line as is, spaces included
+	if rawArgCommands[name] {
+		args = nil
+		if rest := strings.TrimSpace(strings.TrimPrefix(cmd, name)); rest != "" {
+			args = []string{rest}
+		}
+	}
+
 	// Build command handler map
 	commandHandlers := buildCommandHandlerMap()
 
 	// Look up and execute command handler
-	if handler, exists := commandHandlers[cmd]; exists {
-		return handler(m)
+	if handler, exists := commandHandlers[name]; exists {
+		return handler(m, args)
 	}
 
 	// Unknown command
@@ -281,9 +372,3 @@ func autoRefreshTickCmd(intervalSec int) tea.Cmd {
 		return AutoRefreshTickMsg{}
 	})
 }
-
-func logFollowTickCmd() tea.Cmd {
-	return tea.Tick(1*time.Second, func(time.Time) tea.Msg {
-		return LogFollowTickMsg{}
-	})
-}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"gdocker/models"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxConfirmItems caps how many items a confirmation dialog lists by name.
+const maxConfirmItems = 8
+
+// renderConfirm renders a confirmation dialog: the question, what will be
+// removed and what else is affected.
+func renderConfirm(m *models.Model, c *models.Confirmation, maxWidth int) string {
+	width := min(64, maxWidth-4)
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError)).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorWarning))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorForeground))
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(c.Title) + "\n\n")
+
+	s.WriteString(mutedStyle.Render("Will be removed:") + "\n")
+	for i, name := range c.Items {
+		if i == maxConfirmItems {
+			s.WriteString(mutedStyle.Render(fmt.Sprintf("  ... and %d more", len(c.Items)-i)) + "\n")
+			break
+		}
+		s.WriteString(textStyle.Render("  • "+name) + "\n")
+	}
+
+	if len(c.Dependents) > 0 {
+		s.WriteString("\n" + mutedStyle.Render("Affected:") + "\n")
+		for _, line := range c.Dependents {
+			s.WriteString(warnStyle.Render("  ⚠ "+line) + "\n")
+		}
+	}
+
+	s.WriteString("\n" + mutedStyle.Render(confirmHints(m)))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(ColorError)).
+		Render(s.String())
+}
+
+// overlay draws box centered over background, which stays visible around
+// it.
+func overlay(background, box string, width, height int) string {
+	lines := strings.Split(background, "\n")
+	boxLines := strings.Split(box, "\n")
+	boxWidth := lipgloss.Width(box)
+	top := max(0, (height-len(boxLines))/2)
+	left := max(0, (width-boxWidth)/2)
+
+	for i, line := range boxLines {
+		row := top + i
+		if row >= len(lines) {
+			break
+		}
+		prefix := ansi.Truncate(lines[row], left, "")
+		prefix += strings.Repeat(" ", left-ansi.StringWidth(prefix))
+		lines[row] = prefix + ansi.ResetStyle + line + ansi.TruncateLeft(lines[row], left+boxWidth, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"gdocker/models"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Widths of the fixed events timeline columns; attributes take the rest.
+const (
+	eventTimeWidth   = 19
+	eventTypeWidth   = 9
+	eventActionWidth = 16
+	eventNameWidth   = 24
+)
+
+// RenderEvents renders the scrolling timeline of daemon events.
+func RenderEvents(m *models.Model, width, height int) string {
+	var s strings.Builder
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+
+	subtitle := fmt.Sprintf("%d of %d events", len(m.Items), len(m.EventLog))
+	if !m.EventFilter.Empty() {
+		subtitle += " • " + m.EventFilter.String()
+	}
+	if m.EventSince != "" {
+		subtitle += " • since " + m.EventSince
+	}
+	switch {
+	case m.EventLoading:
+		subtitle += " • loading..."
+	case m.EventStream != nil:
+		subtitle += " • live"
+	default:
+		subtitle += " • disconnected"
+	}
+	s.WriteString(renderPaneHeader("Events [7]", subtitle))
+
+	if len(m.Items) == 0 {
+		empty := "No events yet; changes to containers, images, volumes and networks appear here"
+		if !m.EventFilter.Empty() {
+			empty = "No events match the filter (:events clear to reset)"
+		}
+		s.WriteString(mutedStyle.Render(empty))
+		return s.String()
+	}
+
+	attrWidth := max(width-eventTimeWidth-eventTypeWidth-eventActionWidth-eventNameWidth-4, 0)
+	header := padCell("TIME", eventTimeWidth) + " " +
+		padCell("TYPE", eventTypeWidth) + " " +
+		padCell("ACTION", eventActionWidth) + " " +
+		padCell("NAME", eventNameWidth)
+	if attrWidth > 0 {
+		header += " " + padCell("ATTRIBUTES", attrWidth)
+	}
+	s.WriteString(mutedStyle.Bold(true).Render(header) + "\n")
+
+	// Visible window around the cursor
+	maxVisible := max(height-5, 1)
+	start := 0
+	if len(m.Items) > maxVisible {
+		start = min(max(m.Cursor-maxVisible/2, 0), len(m.Items)-maxVisible)
+	}
+	end := min(start+maxVisible, len(m.Items))
+
+	for i := start; i < end; i++ {
+		e := m.Items[i].Event
+		action := padCell(e.Action, eventActionWidth)
+		if i != m.Cursor {
+			action = eventActionStyle(e.Action).Render(action)
+		}
+		row := padCell(e.Time.Format("2006-01-02 15:04:05"), eventTimeWidth) + " " +
+			padCell(e.Type, eventTypeWidth) + " " +
+			action + " " +
+			padCell(e.Name(), eventNameWidth)
+		if attrWidth > 0 {
+			row += " " + padCell(eventAttributes(e), attrWidth)
+		}
+
+		if i == m.Cursor {
+			row = lipgloss.NewStyle().Background(lipgloss.Color(ColorHighlight)).Bold(true).Render(row)
+		}
+		s.WriteString(row + "\n")
+	}
+
+	return s.String()
+}
+
+// eventActionStyle colours failures red and containers coming up green.
+func eventActionStyle(action string) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case action == "die" || action == "oom" || action == "kill" || action == "destroy" ||
+		strings.HasSuffix(action, "unhealthy"):
+		return style.Foreground(lipgloss.Color(ColorError))
+	case action == "start" || action == "create" || strings.HasSuffix(action, ": healthy"):
+		return style.Foreground(lipgloss.Color(ColorSuccess))
+	}
+	return style
+}
+
+// eventAttributes lists an event's attributes as sorted key=value pairs,
+// leaving out the name already shown in its own column.
+func eventAttributes(e *models.DockerEvent) string {
+	keys := make([]string, 0, len(e.Attributes))
+	for k := range e.Attributes {
+		if k != "name" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + e.Attributes[k]
+	}
+	return strings.Join(pairs, " ")
+}
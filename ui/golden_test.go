@@ -0,0 +1,456 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gdocker/config"
+	"gdocker/docker"
+	"gdocker/docker/dockertest"
+	"gdocker/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moby/moby/api/types/container"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+func TestMain(m *testing.M) {
+	// Snapshots must not depend on the terminal or timezone of the machine
+	// running the tests.
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// sizes are the terminal sizes every view is rendered at, including odd and
+// cramped ones where layout regressions show up first.
+var sizes = []struct{ width, height int }{
+	{80, 24},
+	{120, 40},
+	{61, 17},
+}
+
+// assertGolden checks that out fits in width x height cells and compares
+// it with testdata/<name>.golden, or rewrites the file with -update. ANSI
+// sequences are stripped unless keepANSI is set.
+func assertGolden(t *testing.T, name, out string, width, height int, keepANSI bool) {
+	t.Helper()
+	if h := lipgloss.Height(out); h > height {
+		t.Errorf("%s: %d lines, more than the %d available", name, h, height)
+	}
+	for i, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > width {
+			t.Errorf("%s: line %d is %d cells wide, more than the %d available", name, i+1, w, width)
+		}
+	}
+	if !keepANSI {
+		out = ansi.Strip(out)
+	}
+	// Trailing spaces are invisible in diffs and editors strip them
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	out = strings.Join(lines, "\n") + "\n"
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./ui -update to create it)", err)
+	}
+	if out == string(want) {
+		return
+	}
+	got, exp := strings.Split(out, "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < max(len(got), len(exp)); i++ {
+		var g, e string
+		if i < len(got) {
+			g = got[i]
+		}
+		if i < len(exp) {
+			e = exp[i]
+		}
+		if g != e {
+			t.Fatalf("%s differs at line %d:\n got: %q\nwant: %q\n(run go test ./ui -update to accept)", path, i+1, g, e)
+		}
+	}
+}
+
+// fixtureFake is a small host: one standalone container, a compose project
+// with a stopped service, and one of each other resource.
+func fixtureFake() *dockertest.Fake {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	sample := container.StatsResponse{}
+	sample.CPUStats.CPUUsage.TotalUsage = 400
+	sample.CPUStats.SystemUsage = 2000
+	sample.CPUStats.OnlineCPUs = 2
+	sample.PreCPUStats.CPUUsage.TotalUsage = 200
+	sample.PreCPUStats.SystemUsage = 1000
+	sample.MemoryStats.Usage = 64 << 20
+	sample.MemoryStats.Limit = 512 << 20
+	sample.PidsStats.Current = 7
+
+	fake := dockertest.New()
+	fake.AddContainer(dockertest.Container{
+		ID:    "a1b2c3d4e5f6a7b8",
+		Name:  "nginx",
+		Image: "nginx:1.27",
+		Env:   []string{"NGINX_PORT=80", "TZ=UTC"},
+		Ports: []container.PortSummary{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+		Logs: []dockertest.LogLine{
+			{Time: base, Text: "start worker processes"},
+			{Time: base.Add(time.Second), Stderr: true, Text: "[error] 29#29: open() \"/usr/share/nginx/html/favicon.ico\" failed"},
+			{Time: base.Add(2 * time.Second), Text: `{"level":"info","msg":"GET / 200","ms":3}`},
+			{Time: base.Add(3 * time.Second), Text: "WARN upstream slow to respond"},
+		},
+		Stats: []container.StatsResponse{sample, sample, sample},
+	})
+	fake.AddContainer(dockertest.Container{ID: "b1b2c3d4e5f6a7b8", Name: "shop-api-1", Image: "shop/api:2.3", Project: "shop", Service: "api", Stats: []container.StatsResponse{sample},
+		Logs: []dockertest.LogLine{
+			{Time: base, Text: "ERROR connect to db: connection refused"},
+			{Time: base.Add(time.Second), Text: "retrying in 1s"},
+			{Time: base.Add(2 * time.Second), Text: "ERROR connect to db: connection refused"},
+		},
+	})
+	fake.AddContainer(dockertest.Container{ID: "c1b2c3d4e5f6a7b8", Name: "shop-db-1", Image: "postgres:16", Project: "shop", Service: "db", State: "exited", Volumes: []string{"shop_pgdata"}})
+	fake.AddVolume("shop_pgdata", "local")
+	fake.AddImage("0123456789abcdef0123456789abcdef", 187<<20, "nginx:1.27")
+	fake.AddNetwork("fedcba9876543210", "shop_default", "bridge")
+	return fake
+}
+
+// fixtureModel loads fixtureFake into a model of the given size.
+func fixtureModel(t *testing.T, width, height int) models.Model {
+	t.Helper()
+	m := models.NewModel(fixtureFake(), docker.Services{}, Renderer{})
+	m.KeyBindings = config.Default()
+	m.Width, m.Height = width, height
+	for _, load := range []func(*models.Model) tea.Cmd{docker.RefreshContainers, docker.ReloadVolumes, docker.ReloadImages, docker.ReloadNetworks} {
+		msg := load(&m)()
+		if failed, ok := msg.(models.ResourceFailedMsg); ok {
+			t.Fatalf("loading %s: %v", failed.Resource, failed.Err)
+		}
+		next, _ := m.Update(msg)
+		m = next.(models.Model)
+	}
+	return m
+}
+
+// press sends keys to the model, runs the commands they return and feeds
+// the resulting messages back, like the Bubble Tea runtime does.
+func press(t *testing.T, m models.Model, keys ...string) models.Model {
+	t.Helper()
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m = send(t, m, msg)
+	}
+	return m
+}
+
+// typeText enters text one key at a time, e.g. after ":" or "?".
+func typeText(t *testing.T, m models.Model, text string) models.Model {
+	t.Helper()
+	for _, r := range text {
+		m = press(t, m, string(r))
+	}
+	return m
+}
+
+// send updates the model with msg and settles the commands that follow.
+// Commands still running after a short wait, such as ticks and open
+// streams, are dropped.
+func send(t *testing.T, m models.Model, msg tea.Msg) models.Model {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	m = next.(models.Model)
+
+	queue := []tea.Cmd{cmd}
+	for steps := 0; len(queue) > 0 && steps < 50; steps++ {
+		c := queue[0]
+		queue = queue[1:]
+		if c == nil {
+			continue
+		}
+		done := make(chan tea.Msg, 1)
+		go func() { done <- c() }()
+		select {
+		case msg := <-done:
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				queue = append(queue, batch...)
+				continue
+			}
+			next, cmd := m.Update(msg)
+			m = next.(models.Model)
+			queue = append(queue, cmd)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return m
+}
+
+func TestRenderViews(t *testing.T) {
+	views := []struct {
+		name  string
+		setup func(*testing.T, models.Model) models.Model
+	}{
+		{"containers", func(t *testing.T, m models.Model) models.Model {
+			return press(t, m, "j", " ", "j")
+		}},
+		{"project", func(t *testing.T, m models.Model) models.Model {
+			return press(t, m, "j")
+		}},
+		{"volumes", func(t *testing.T, m models.Model) models.Model { return press(t, m, "2") }},
+		{"images", func(t *testing.T, m models.Model) models.Model { return press(t, m, "3") }},
+		{"networks", func(t *testing.T, m models.Model) models.Model { return press(t, m, "4") }},
+		{"confirm_delete", func(t *testing.T, m models.Model) models.Model { return press(t, m, "2", "d") }},
+		{"ports", func(t *testing.T, m models.Model) models.Model { return press(t, m, "p") }},
+		{"env", func(t *testing.T, m models.Model) models.Model { return press(t, m, "v") }},
+		{"logs", func(t *testing.T, m models.Model) models.Model {
+			m.LogRangeUntil = "2024-05-02T00:00:00Z" // a fixed range does not follow
+			return press(t, m, "l")
+		}},
+		{"logs_search", func(t *testing.T, m models.Model) models.Model {
+			m.LogRangeUntil = "2024-05-02T00:00:00Z"
+			m = press(t, m, "l", "?")
+			return press(t, typeText(t, m, "error"), "enter")
+		}},
+		{"stats", func(t *testing.T, m models.Model) models.Model { return press(t, m, "t") }},
+		{"top", func(t *testing.T, m models.Model) models.Model { return press(t, m, "5") }},
+		{"events", func(t *testing.T, m models.Model) models.Model {
+			// Delivered as if read from the stream, without asking for more
+			next, _ := m.Update(models.DockerEventsMsg{Events: []models.DockerEvent{
+				{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Type: "container", Action: "start", ActorID: "a1b2c3d4e5f6", Attributes: map[string]string{"name": "nginx", "image": "nginx:1.27"}},
+				{Time: time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC), Type: "container", Action: "die", ActorID: "c1b2c3d4e5f6", Attributes: map[string]string{"name": "shop-db-1", "exitCode": "137"}},
+				{Time: time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC), Type: "volume", Action: "create", ActorID: "shop_pgdata", Attributes: map[string]string{"driver": "local"}},
+			}})
+			return press(t, next.(models.Model), "7")
+		}},
+		{"help", func(t *testing.T, m models.Model) models.Model {
+			return press(t, typeText(t, m, ":help"), "enter")
+		}},
+		{"command", func(t *testing.T, m models.Model) models.Model { return typeText(t, m, ":events type") }},
+	}
+
+	for _, view := range views {
+		for _, size := range sizes {
+			name := fmt.Sprintf("%s_%dx%d", view.name, size.width, size.height)
+			t.Run(name, func(t *testing.T) {
+				m := view.setup(t, fixtureModel(t, size.width, size.height))
+				defer stopStreams(&m)
+				assertGolden(t, name, m.View(), m.Width, m.Height, false)
+			})
+		}
+	}
+}
+
+// TestKeySequences drives whole interactions through Model.Update and
+// snapshots where they end up.
+func TestKeySequences(t *testing.T) {
+	sequences := []struct {
+		name string
+		keys []string
+	}{
+		// Expand the project, open the api logs and step to the next error
+		{"project_logs_search", []string{"j", " ", "j", "l", "?", "e", "r", "r", "o", "r", "enter", "n"}},
+		// Walk through every resource view and back
+		{"views_roundtrip", []string{"2", "3", "4", "5", "1", "j"}},
+		// Start a command, cancel it and keep navigating
+		{"command_then_cancel", []string{":", "e", "v", "esc", "j"}},
+		// Mark nginx and the stopped shop service
+		{"marked_containers", []string{"m", " ", "j", "j", "m"}},
+		// Scroll the help to its end and back up a line
+		{"help_scrolled", []string{":", "h", "e", "l", "p", "enter", "G", "k"}},
+	}
+	for _, seq := range sequences {
+		t.Run(seq.name, func(t *testing.T) {
+			m := fixtureModel(t, 100, 30)
+			m.LogRangeUntil = "2024-05-02T00:00:00Z"
+			m = press(t, m, seq.keys...)
+			defer stopStreams(&m)
+			assertGolden(t, "seq_"+seq.name, m.View(), m.Width, m.Height, false)
+		})
+	}
+}
+
+// TestRenderStartup snapshots the lists before and without a daemon.
+func TestRenderStartup(t *testing.T) {
+	fake := fixtureFake()
+	m := models.NewModel(fake, docker.Services{}, Renderer{})
+	m.KeyBindings = config.Default()
+	m.Width, m.Height = 80, 24
+	m.StartLoading()
+	assertGolden(t, "startup_loading", m.View(), m.Width, m.Height, false)
+
+	fake.SetDown(true)
+	m = send(t, m, docker.RefreshContainers(&m)())
+	m = send(t, m, docker.ReloadVolumes(&m)())
+	assertGolden(t, "startup_disconnected", m.View(), m.Width, m.Height, false)
+
+	// Back up, with one list the daemon refuses to list
+	fake.SetDown(false)
+	fake.Fail("VolumeList", fmt.Errorf("permission denied"))
+	m = send(t, m, models.DaemonReconnectMsg{})
+	m = press(t, m, "2")
+	stopStreams(&m)
+	assertGolden(t, "startup_volumes_failed", m.View(), m.Width, m.Height, false)
+}
+
+// TestRenderOperation snapshots the status bar while a request runs.
+func TestRenderOperation(t *testing.T) {
+	m := press(t, fixtureModel(t, 100, 30), "j")
+	m.StartOperation("Stopping web", 0, func(ctx context.Context) tea.Msg {
+		<-ctx.Done()
+		return nil
+	})
+	m.StartOperation("Inspecting web", 0, func(ctx context.Context) tea.Msg {
+		<-ctx.Done()
+		return nil
+	})
+	assertGolden(t, "operation_running", m.View(), m.Width, m.Height, false)
+	m.CancelOperations()
+}
+
+// TestRenderReadOnly snapshots the badge and the hints and help without
+// the disabled actions.
+func TestRenderReadOnly(t *testing.T) {
+	m := fixtureModel(t, 120, 40)
+	m.ReadOnly = true
+	assertGolden(t, "readonly", m.View(), m.Width, m.Height, false)
+	m = press(t, typeText(t, m, ":help"), "enter")
+	assertGolden(t, "readonly_help", m.View(), m.Width, m.Height, false)
+}
+
+// TestRenderRemappedKeys checks that hints follow the configured bindings
+// instead of the defaults.
+func TestRenderRemappedKeys(t *testing.T) {
+	m := fixtureModel(t, 120, 40)
+	m.KeyBindings.Container.Restart = []string{"R"}
+	m.KeyBindings.Logs.LoadOlder = []string{"O"}
+	m.KeyBindings.Logs.Search = []string{"/"}
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "R: restart") || strings.Contains(out, "r: restart") {
+		t.Errorf("containers view does not show the remapped restart key:\n%s", out)
+	}
+	m.ReadOnly = true
+	if out := ansi.Strip(m.View()); strings.Contains(out, "R: restart") {
+		t.Errorf("read-only containers view shows the remapped restart key:\n%s", out)
+	}
+	m.ReadOnly = false
+	m = press(t, m, "l")
+	defer stopStreams(&m)
+	out := ansi.Strip(m.View())
+	for _, want := range []string{"↑ O: load older lines", "/: search"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs view does not show %q:\n%s", want, out)
+		}
+	}
+	m.Height = 120
+	m = press(t, typeText(t, m, ":help"), "enter")
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "O            Load older lines") {
+		t.Errorf("help does not show the remapped load-older key:\n%s", out)
+	}
+}
+
+// TestRenderContext shows the active Docker context in the header.
+func TestRenderContext(t *testing.T) {
+	m := fixtureModel(t, 120, 40)
+	m.Context = "prod"
+	assertGolden(t, "context_header", m.View(), m.Width, m.Height, false)
+}
+
+// TestRenderColors keeps the styling of the main views under test too.
+func TestRenderColors(t *testing.T) {
+	m := press(t, fixtureModel(t, 120, 40), "j", " ", "j")
+	assertGolden(t, "containers_ansi", m.View(), m.Width, m.Height, true)
+
+	m = fixtureModel(t, 120, 40)
+	m.LogRangeUntil = "2024-05-02T00:00:00Z"
+	m = press(t, m, "l")
+	assertGolden(t, "logs_ansi", m.View(), m.Width, m.Height, true)
+}
+
+// TestRenderListAndDetails snapshots the two panes on their own, at widths
+// narrower than any full view uses.
+func TestRenderListAndDetails(t *testing.T) {
+	m := press(t, fixtureModel(t, 120, 40), "j", " ")
+	for _, width := range []int{20, 33, 47} {
+		assertGolden(t, fmt.Sprintf("list_w%d", width), RenderList(&m, width, 20), width, 20, false)
+		assertGolden(t, fmt.Sprintf("details_w%d", width), RenderDetails(&m, width, 20), width, 20, false)
+	}
+}
+
+// TestRenderStatsFits checks that the stats pane drops charts and rows
+// rather than growing past the size it is given.
+func TestRenderStatsFits(t *testing.T) {
+	m := press(t, fixtureModel(t, 120, 40), "t")
+	defer stopStreams(&m)
+	for height := 1; height <= 40; height++ {
+		for _, width := range []int{20, 39, 52, 78} {
+			out := RenderStats(&m, width, height)
+			if h := lipgloss.Height(out); h > height {
+				t.Errorf("%dx%d: %d lines", width, height, h)
+			}
+			for _, line := range strings.Split(out, "\n") {
+				if w := lipgloss.Width(line); w > width {
+					t.Errorf("%dx%d: line of %d cells: %q", width, height, w, ansi.Strip(line))
+				}
+			}
+		}
+	}
+}
+
+// TestRenderTinyTerminal checks that no view panics when the terminal is
+// far too small to lay it out.
+func TestRenderTinyTerminal(t *testing.T) {
+	for _, keys := range [][]string{{}, {"2"}, {"l"}, {"t"}, {"5"}, {"7"}, {"v"}, {"p"}} {
+		for _, size := range []struct{ width, height int }{{1, 1}, {10, 3}, {24, 6}} {
+			m := press(t, fixtureModel(t, size.width, size.height), keys...)
+			_ = m.View()
+			stopStreams(&m)
+		}
+	}
+}
+
+func stopStreams(m *models.Model) {
+	if m.LogStream != nil {
+		m.LogStream.Cancel()
+	}
+	if m.StatsStream != nil {
+		m.StatsStream.Cancel()
+	}
+	if m.EventStream != nil {
+		m.EventStream.Cancel()
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"gdocker/models"
+	"strings"
+)
+
+// hint is one "key: action" entry of a line of shortcuts. Keys come from
+// the configured bindings, so the hints stay right after a remap.
+type hint struct {
+	key      string
+	desc     string
+	mutating bool // Hidden in read-only mode
+}
+
+// keyLabel names the first key of a binding the way hints write keys, or
+// returns "" when the action is unbound.
+func keyLabel(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	if keys[0] == " " {
+		return "space"
+	}
+	return keys[0]
+}
+
+// keyPair names two related bindings, e.g. "j/k" or "n/N".
+func keyPair(a, b []string) string {
+	if keyLabel(a) == "" || keyLabel(b) == "" {
+		return keyLabel(a) + keyLabel(b)
+	}
+	return keyLabel(a) + "/" + keyLabel(b)
+}
+
+// switchKeys are the bindings that open the navigation modes, in order.
+func switchKeys(m *models.Model) [][]string {
+	nav := m.KeySettings().Navigation
+	return [][]string{nav.SwitchContainer, nav.SwitchVolume, nav.SwitchImage, nav.SwitchNetwork, nav.SwitchTop, nav.SwitchAlerts, nav.SwitchEvents}
+}
+
+// switchLabel names the keys of the first n navigation modes: "1-4" while
+// they are the default digits, otherwise each key.
+func switchLabel(m *models.Model, n int) string {
+	var labels []string
+	digits := true
+	for i, keys := range switchKeys(m)[:n] {
+		label := keyLabel(keys)
+		if label == "" {
+			continue
+		}
+		digits = digits && label == string(rune('1'+i))
+		labels = append(labels, label)
+	}
+	if digits && len(labels) == n && n > 1 {
+		return labels[0] + "-" + labels[n-1]
+	}
+	return strings.Join(labels, "/")
+}
+
+// titleKey is the " [key]" suffix of a list title, naming the key that
+// opens the list.
+func titleKey(m *models.Model, mode models.NavigationMode) string {
+	if label := keyLabel(switchKeys(m)[mode]); label != "" {
+		return " [" + label + "]"
+	}
+	return ""
+}
+
+// hintLine joins hints as "key: action" entries, leaving out unbound ones
+// and, in read-only mode, the disabled actions.
+func hintLine(m *models.Model, hints ...hint) string {
+	return joinHints(m, hints, ": ")
+}
+
+// actionsLine lists the actions of a details pane as "Actions: key action".
+func actionsLine(m *models.Model, hints ...hint) string {
+	return "Actions: " + joinHints(m, hints, " ")
+}
+
+func joinHints(m *models.Model, hints []hint, sep string) string {
+	parts := make([]string, 0, len(hints))
+	for _, h := range hints {
+		if h.key == "" || (h.mutating && m.ReadOnly) {
+			continue
+		}
+		parts = append(parts, h.key+sep+h.desc)
+	}
+	return strings.Join(parts, " • ")
+}
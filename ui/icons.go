@@ -14,6 +14,8 @@ const (
 	IconVolume  = "◉"
 	IconImage   = "▢"
 	IconNetwork = "⬡"
+	IconAlert   = "⚠"
+	IconCleared = "✓"
 	IconDocker  = "🐳"
 )
 
@@ -22,6 +24,9 @@ const (
 	IconExpanded    = "▼"
 	IconCollapsed   = "▶"
 	IconCursor      = ">"
+	IconMarked      = "*"
+	IconReadOnly    = "⊘"
+	IconContext     = "⇄"
 	IconNoCursor    = " "
 	IconInputCursor = "█"
 )
@@ -57,6 +62,18 @@ const (
 	ColorSearchHighlightBG = "#ffff00" // Yellow (search highlight background)
 )
 
+// ServiceColors is the palette used to tell services apart in project logs
+var ServiceColors = []string{
+	"#569cd6", // Blue
+	"#4ec9b0", // Green
+	"#dcdcaa", // Yellow
+	"#c586c0", // Purple
+	"#ce9178", // Orange
+	"#9cdcfe", // Light blue
+	"#b5cea8", // Light green
+	"#d16969", // Red
+}
+
 // GetContainerStatusIcon returns the icon for a container status
 func GetContainerStatusIcon(status string) string {
 	switch status {
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"gdocker/models"
+	"strings"
+)
+
+// maxJSONColumnWidth caps how wide a non-final JSON column may grow.
+const maxJSONColumnWidth = 24
+
+// jsonColumnWidths sizes each chosen field to the widest value in the
+// visible window so columns line up. The last field is left unpadded.
+func jsonColumnWidths(m *models.Model, start, end int, keys []string) []int {
+	widths := make([]int, len(keys))
+	for i := start; i < end; i++ {
+		fields, ok := m.Logs[m.VisibleLogs[i]].JSON()
+		if !ok {
+			continue
+		}
+		for k, key := range keys {
+			if v, ok := models.LookupField(fields, key); ok {
+				widths[k] = min(max(widths[k], len(formatJSONColumn(key, v))), maxJSONColumnWidth)
+			}
+		}
+	}
+	return widths
+}
+
+// formatJSONColumn renders a single field value for the column view.
+// Levels are upper-cased so they read the same across loggers.
+func formatJSONColumn(key string, v any) string {
+	value := models.FormatFieldValue(v)
+	if key == "level" || key == "lvl" || key == "severity" {
+		value = strings.ToUpper(value)
+	}
+	return value
+}
+
+// formatJSONColumns lays out a JSON log line as a short time followed by the
+// chosen fields. Fields that are not shown are summarised as a count.
+func formatJSONColumns(entry models.LogEntry, fields map[string]any, keys []string, widths []int) string {
+	var parts []string
+	if !entry.Time.IsZero() {
+		parts = append(parts, entry.Time.Local().Format("15:04:05.000"))
+	}
+
+	shown := 0
+	for k, key := range keys {
+		value := ""
+		if v, ok := models.LookupField(fields, key); ok {
+			value = formatJSONColumn(key, v)
+			if _, top := fields[key]; top {
+				shown++
+			}
+		}
+		if k < len(keys)-1 {
+			if len(value) > widths[k] {
+				value = value[:max(widths[k]-1, 0)] + "…"
+			}
+			value = fmt.Sprintf("%-*s", widths[k], value)
+		}
+		parts = append(parts, value)
+	}
+
+	line := strings.Join(parts, "  ")
+	if extra := len(fields) - shown; extra > 0 {
+		line += fmt.Sprintf("  +%d fields", extra)
+	}
+	return line
+}
+
+// logDetailLines expands a log entry into an indented JSON tree, or explains
+// why it cannot. At most limit lines are returned.
+func logDetailLines(entry models.LogEntry, limit int) []string {
+	fields, ok := entry.JSON()
+	if !ok {
+		return []string{"  (line is not a JSON object)"}
+	}
+
+	data, err := json.MarshalIndent(fields, "  ", "  ")
+	if err != nil {
+		return []string{"  " + err.Error()}
+	}
+
+	lines := strings.Split("  "+string(data), "\n")
+	if limit < 2 {
+		limit = 2
+	}
+	if len(lines) > limit {
+		more := len(lines) - limit + 1
+		lines = append(lines[:limit-1], fmt.Sprintf("  ... %d more lines", more))
+	}
+	return lines
+}
+
+// levelColor is the text colour for a classified log line, or "" to keep
+// the default.
+func levelColor(level models.LogLevel) string {
+	switch level {
+	case models.LevelError:
+		return ColorError
+	case models.LevelWarn:
+		return ColorWarning
+	case models.LevelDebug:
+		return ColorMuted
+	default:
+		return ""
+	}
+}
+
+// formatLevelCounts summarises the buffer by level, e.g. "E:3 W:12 I:240 D:0".
+func formatLevelCounts(logs []models.LogEntry) string {
+	var counts [models.LevelError + 1]int
+	for _, entry := range logs {
+		counts[entry.Level]++
+	}
+	return fmt.Sprintf("E:%d W:%d I:%d D:%d",
+		counts[models.LevelError], counts[models.LevelWarn], counts[models.LevelInfo], counts[models.LevelDebug])
+}
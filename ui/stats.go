@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"gdocker/models"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chartBlocks are the eighth-height bars used to draw stats charts.
+var chartBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// renderChart draws values as a bar chart rows lines tall, scaled so peak
+// fills the full height. Only the newest width values are shown, right
+// aligned so the chart grows from the right like a scrolling graph.
+func renderChart(values []float64, width, rows int, peak float64, color string) string {
+	if width < 1 || rows < 1 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	// A metric that never moved needs no more than a baseline
+	if peak <= 0 {
+		peak = 1
+		rows = 1
+	}
+
+	pad := width - len(values)
+	lines := make([]string, rows)
+	for row := range rows {
+		var b strings.Builder
+		b.WriteString(strings.Repeat(" ", pad))
+		// Eighths of height below this row, counting from the bottom
+		floor := (rows - 1 - row) * 8
+		for _, v := range values {
+			level := int(v / peak * float64(rows*8))
+			// Keep any non-zero value visible on the bottom row
+			if v > 0 && level == 0 {
+				level = 1
+			}
+			b.WriteRune(chartBlocks[min(max(level-floor, 0), 8)])
+		}
+		lines[row] = "  " + b.String()
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(strings.Join(lines, "\n")) + "\n"
+}
+
+// statsSeries extracts one metric from the stats history with its peak.
+func statsSeries(history []models.ContainerStats, metric func(models.ContainerStats) float64) ([]float64, float64) {
+	values := make([]float64, len(history))
+	peak := 0.0
+	for i, sample := range history {
+		values[i] = metric(sample)
+		peak = max(peak, values[i])
+	}
+	return values, peak
+}
+
+// formatRate renders a byte rate such as "1.20 MiB/s".
+func formatRate(bytesPerSec float64) string {
+	return formatBytes(uint64(bytesPerSec)) + "/s"
+}
+
+// statsLayout is what the stats view shows in the lines it has.
+type statsLayout struct {
+	chartRows int  // Height of each chart, 0 hides them
+	spacing   bool // Blank lines between the sections
+	blockIO   bool // The block I/O section
+	pids      bool // The PIDs row
+}
+
+// maxStatsChartRows caps the height of each chart.
+const maxStatsChartRows = 4
+
+// lines counts the lines the layout takes.
+func (l statsLayout) lines() int {
+	sections, metrics := 3, 4 // CPU, memory usage, rx and tx
+	if l.blockIO {
+		sections, metrics = 4, 6
+	}
+	n := 3 + sections + metrics + 1 // Pane header, section titles, metric rows, status
+	if l.pids {
+		n++
+	}
+	if l.spacing {
+		n += sections // Between the sections and above the status
+	}
+	return n + l.chartRows*metrics
+}
+
+// fitStats lays the stats view out in height lines. The charts grow while
+// there is room for a row each; without it they go first, then the blank
+// lines, the block I/O section and the PIDs row.
+func fitStats(height int) statsLayout {
+	l := statsLayout{spacing: true, blockIO: true, pids: true}
+	metrics := 6
+	if rows := (height - l.lines()) / metrics; rows > 0 {
+		l.chartRows = min(rows, maxStatsChartRows)
+		return l
+	}
+	for _, drop := range []*bool{&l.spacing, &l.blockIO, &l.pids} {
+		if l.lines() <= height {
+			break
+		}
+		*drop = false
+	}
+	return l
+}
+
+// renderStatsMetric renders a metric row followed by its history chart.
+// The peak is shown next to the value where the width leaves room for it.
+func renderStatsMetric(label, value string, history []models.ContainerStats, metric func(models.ContainerStats) float64, format func(float64) string, color string, width, rows int) string {
+	values, peak := statsSeries(history, metric)
+	row := value
+	if len(values) > 1 {
+		peakText := "peak " + format(peak)
+		// The label column takes 11 cells
+		valueWidth := min(32, width-11-len(peakText)-1)
+		if lipgloss.Width(value) <= valueWidth {
+			row = fmt.Sprintf("%-*s ", valueWidth, value) + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(peakText)
+		}
+	}
+	return renderMetricRow(label, row) + renderChart(values, width-4, rows, peak, color)
+}
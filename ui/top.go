@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"fmt"
+	"gdocker/models"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// topColumn describes one column of the overview table. Name is the only
+// column that stretches to fill the width.
+type topColumn struct {
+	title  string
+	width  int
+	column models.TopColumn
+	value  func(models.ContainerStats) string
+}
+
+var topColumns = []topColumn{
+	{"CPU %", 8, models.TopColumnCPU, func(s models.ContainerStats) string {
+		return fmt.Sprintf("%.2f%%", s.CPUPercent)
+	}},
+	{"MEM USAGE / LIMIT", 21, models.TopColumnMem, func(s models.ContainerStats) string {
+		return formatBytes(s.MemUsage) + " / " + formatBytes(s.MemLimit)
+	}},
+	{"MEM %", 7, models.TopColumnMem, func(s models.ContainerStats) string {
+		return fmt.Sprintf("%.2f%%", s.MemPercent)
+	}},
+	{"NET RX", 12, models.TopColumnNetRx, func(s models.ContainerStats) string {
+		return formatRate(s.NetRxRate)
+	}},
+	{"NET TX", 12, models.TopColumnNetTx, func(s models.ContainerStats) string {
+		return formatRate(s.NetTxRate)
+	}},
+	{"BLOCK R / W", 25, models.TopColumnBlock, func(s models.ContainerStats) string {
+		return formatRate(s.BlockReadRate) + " / " + formatRate(s.BlockWriteRate)
+	}},
+	{"PIDS", 5, models.TopColumnPIDs, func(s models.ContainerStats) string {
+		return fmt.Sprintf("%d", s.PIDs)
+	}},
+}
+
+// projectColumns are the columns of the project resource panel. Memory
+// limits are per container, so only usage is summed.
+var projectColumns = []topColumn{
+	{"CPU %", 8, models.TopColumnCPU, func(s models.ContainerStats) string {
+		return fmt.Sprintf("%.2f%%", s.CPUPercent)
+	}},
+	{"MEM", 10, models.TopColumnMem, func(s models.ContainerStats) string {
+		return formatBytes(s.MemUsage)
+	}},
+	{"NET RX", 12, models.TopColumnNetRx, func(s models.ContainerStats) string {
+		return formatRate(s.NetRxRate)
+	}},
+	{"NET TX", 12, models.TopColumnNetTx, func(s models.ContainerStats) string {
+		return formatRate(s.NetTxRate)
+	}},
+	{"BLOCK R / W", 25, models.TopColumnBlock, func(s models.ContainerStats) string {
+		return formatRate(s.BlockReadRate) + " / " + formatRate(s.BlockWriteRate)
+	}},
+}
+
+// minTopNameWidth keeps container names readable on narrow terminals; the
+// rightmost columns are dropped first.
+const minTopNameWidth = 16
+
+// RenderTop renders the docker stats-like overview of running containers.
+func RenderTop(m *models.Model, width, height int) string {
+	var s strings.Builder
+
+	order := "▼"
+	if m.TopSortAsc {
+		order = "▲"
+	}
+	subtitle := fmt.Sprintf("%d running • sorted by %s %s", len(m.Items), m.TopSort, order)
+	if m.TopLoading {
+		subtitle += " • loading..."
+	}
+	s.WriteString(renderPaneHeader("Top [5]", subtitle))
+
+	if len(m.Items) == 0 {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render("No running containers"))
+		return s.String()
+	}
+
+	columns, nameWidth := fitTopColumns(topColumns, width)
+
+	// Header row, marking the sort column
+	header := padCell("NAME"+sortMark(m, models.TopColumnName, order), nameWidth)
+	for _, c := range columns {
+		title := c.title
+		if c.title != "MEM %" {
+			title += sortMark(m, c.column, order)
+		}
+		header += " " + padLeft(title, c.width)
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Bold(true).Render(header) + "\n")
+
+	// Visible window around the cursor
+	maxVisible := max(height-5, 1)
+	start := 0
+	if len(m.Items) > maxVisible {
+		start = min(max(m.Cursor-maxVisible/2, 0), len(m.Items)-maxVisible)
+	}
+	end := min(start+maxVisible, len(m.Items))
+
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+	for i := start; i < end; i++ {
+		container := m.Items[i].Container
+		stats, ok := m.TopStats[container.ID]
+		row := topRow(container.Name, stats, ok, columns, nameWidth)
+
+		switch {
+		case i == m.Cursor:
+			row = lipgloss.NewStyle().Background(lipgloss.Color(ColorHighlight)).Bold(true).Render(row)
+		case !ok:
+			row = mutedStyle.Render(row)
+		}
+		s.WriteString(row + "\n")
+	}
+
+	return s.String()
+}
+
+// RenderProjectStats renders the live resource panel of a compose project:
+// the project total followed by one row per service, summing replicas.
+func RenderProjectStats(m *models.Model, project *models.ComposeGroup, width int) string {
+	var s strings.Builder
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+
+	s.WriteString(mutedStyle.Render("Resources") + "\n")
+
+	// Services in first-seen order, with the samples of their running replicas
+	var services []string
+	samples := make(map[string][]models.ContainerStats)
+	running := make(map[string]bool)
+	for _, c := range project.Containers {
+		service := c.Service
+		if service == "" {
+			service = c.ServiceName()
+		}
+		if _, seen := samples[service]; !seen {
+			services = append(services, service)
+			samples[service] = nil
+		}
+		if c.State != "running" {
+			continue
+		}
+		running[service] = true
+		if stats, ok := m.ProjectStats[c.ID]; ok && m.ProjectStatsName == project.Name {
+			samples[service] = append(samples[service], stats)
+		}
+	}
+
+	if len(running) == 0 {
+		s.WriteString(mutedStyle.Render("No running containers") + "\n")
+		return s.String()
+	}
+	if m.ProjectStatsName != project.Name {
+		s.WriteString(mutedStyle.Render("Loading...") + "\n")
+		return s.String()
+	}
+
+	columns, nameWidth := fitTopColumns(projectColumns, width)
+	header := padCell("SERVICE", nameWidth)
+	for _, c := range columns {
+		header += " " + padLeft(c.title, c.width)
+	}
+	s.WriteString(mutedStyle.Bold(true).Render(header) + "\n")
+
+	var all []models.ContainerStats
+	for _, service := range services {
+		all = append(all, samples[service]...)
+	}
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(topRow("TOTAL", sumStats(all), true, columns, nameWidth)) + "\n")
+
+	for _, service := range services {
+		if !running[service] {
+			s.WriteString(mutedStyle.Render(padCell(service, nameWidth)+" stopped") + "\n")
+			continue
+		}
+		name := service
+		if n := len(samples[service]); n > 1 {
+			name = fmt.Sprintf("%s ×%d", service, n)
+		}
+		row := topRow(name, sumStats(samples[service]), len(samples[service]) > 0, columns, nameWidth)
+		if len(samples[service]) == 0 {
+			row = mutedStyle.Render(row)
+		}
+		s.WriteString(row + "\n")
+	}
+
+	return s.String()
+}
+
+// sumStats adds up the usage and rates of several samples.
+func sumStats(samples []models.ContainerStats) models.ContainerStats {
+	var total models.ContainerStats
+	for _, s := range samples {
+		total.CPUPercent += s.CPUPercent
+		total.MemUsage += s.MemUsage
+		total.MemLimit += s.MemLimit
+		total.NetRxRate += s.NetRxRate
+		total.NetTxRate += s.NetTxRate
+		total.BlockReadRate += s.BlockReadRate
+		total.BlockWriteRate += s.BlockWriteRate
+		total.PIDs += s.PIDs
+	}
+	return total
+}
+
+// fitTopColumns drops columns from the right until the name column fits in
+// width, and returns the columns left with the width of the name column.
+func fitTopColumns(columns []topColumn, width int) ([]topColumn, int) {
+	fixed := func() int {
+		total := 0
+		for _, c := range columns {
+			total += c.width + 1
+		}
+		return total
+	}
+	for len(columns) > 0 && width-fixed() < minTopNameWidth {
+		columns = columns[:len(columns)-1]
+	}
+	return columns, max(width-fixed()-1, 1)
+}
+
+// topRow renders one table row; columns without a sample show "-".
+func topRow(name string, stats models.ContainerStats, ok bool, columns []topColumn, nameWidth int) string {
+	row := padCell(name, nameWidth)
+	for _, c := range columns {
+		value := "-"
+		if ok {
+			value = c.value(stats)
+		}
+		row += " " + padLeft(value, c.width)
+	}
+	return row
+}
+
+func sortMark(m *models.Model, column models.TopColumn, order string) string {
+	if m.TopSort == column {
+		return " " + order
+	}
+	return ""
+}
+
+// padCell left-aligns text in width cells, truncating with an ellipsis.
+func padCell(text string, width int) string {
+	if lipgloss.Width(text) > width {
+		runes := []rune(text)
+		text = string(runes[:max(width-1, 0)]) + "…"
+	}
+	return text + strings.Repeat(" ", max(width-lipgloss.Width(text), 0))
+}
+
+// padLeft right-aligns text in width cells.
+func padLeft(text string, width int) string {
+	if lipgloss.Width(text) > width {
+		return padCell(text, width)
+	}
+	return strings.Repeat(" ", width-lipgloss.Width(text)) + text
+}
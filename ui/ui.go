@@ -2,22 +2,61 @@ package ui
 
 import (
 	"fmt"
-	"gdocker/config"
 	"gdocker/models"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type helpEntry struct {
-	key  string
-	desc string
+	key      string
+	desc     string
+	mutating bool // Hidden in read-only mode
 }
 
-func init() {
-	// Set function pointer to avoid circular imports
-	models.RenderViewFunc = RenderView
+// Renderer draws models with the renderers of this package.
+type Renderer struct{}
+
+var _ models.Renderer = Renderer{}
+
+func (Renderer) Frame(m *models.Model) string {
+	return RenderView(m)
+}
+
+func (Renderer) Details(m *models.Model, width, height int) string {
+	return RenderDetails(m, width, height)
+}
+
+func (Renderer) Logs(m *models.Model, width, height int) string {
+	return RenderLogs(m, width, height)
+}
+
+func (Renderer) Ports(m *models.Model, width, height int) string {
+	return RenderPorts(m, width, height)
+}
+
+func (Renderer) Env(m *models.Model, width, height int) string {
+	return RenderEnv(m, width, height)
+}
+
+func (Renderer) Stats(m *models.Model, width, height int) string {
+	return RenderStats(m, width, height)
+}
+
+func (Renderer) Inspect(m *models.Model, width, height int) string {
+	return RenderInspect(m, width, height)
+}
+
+func (Renderer) Top(m *models.Model, width, height int) string {
+	return RenderTop(m, width, height)
+}
+
+func (Renderer) Events(m *models.Model, width, height int) string {
+	return RenderEvents(m, width, height)
 }
 
 func RenderView(m *models.Model) string {
@@ -26,35 +65,44 @@ func RenderView(m *models.Model) string {
 	}
 
 	// If in help mode, show help overlay
-	if m.HelpMode {
+	if m.Input == models.InputHelp {
 		return RenderHelp(m, m.Width, m.Height)
 	}
 
-	leftWidth := m.Width / 3
-	rightWidth := m.Width - leftWidth - 2
-
 	// Render header with stats
 	header := RenderHeader(m, m.Width)
 
-	// Render left panel (container list)
-	left := RenderList(m, leftWidth, m.Height-4) // -4 for header and status
+	// The overview table and the events timeline use the full width
+	var panels string
+	if m.FullWidth() {
+		content := fit(m.ActiveView().View(m, m.Width-4, m.Height-4), m.Width-2, m.Height-4)
+		panels = lipgloss.NewStyle().
+			Width(m.Width - 2).
+			Height(m.Height - 4). // -4 for header and status
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(ColorPrimary)).
+			Render(content)
+	} else {
+		panels = renderPanels(m)
+	}
 
-	// Render right panel based on view mode
-	var right string
-	switch m.ViewMode {
-	case models.ViewLogs:
-		right = RenderLogs(m, rightWidth, m.Height-2)
-	case models.ViewPorts:
-		right = RenderPorts(m, rightWidth, m.Height-2)
-	case models.ViewEnv:
-		right = RenderEnv(m, rightWidth, m.Height-2)
-	case models.ViewStats:
-		right = RenderStats(m, rightWidth, m.Height-2)
-	case models.ViewInspect:
-		right = RenderInspect(m, rightWidth, m.Height-2)
-	default:
-		right = RenderDetails(m, rightWidth, m.Height-2)
+	view := lipgloss.JoinVertical(lipgloss.Left, header, panels, renderStatusBar(m))
+	if m.Input == models.InputConfirm && m.Confirm != nil {
+		view = overlay(view, renderConfirm(m, m.Confirm, m.Width), m.Width, m.Height)
 	}
+	return view
+}
+
+// renderPanels renders the list on the left and the current view on the right.
+func renderPanels(m *models.Model) string {
+	leftWidth := m.Width / 3
+	rightWidth := m.Width - leftWidth - 4 // -4 for the borders of both panels
+
+	// Render left panel (container list)
+	left := fit(RenderList(m, leftWidth, m.Height-4), leftWidth, m.Height-4) // -4 for header and status
+
+	// Render right panel with the active view
+	right := fit(m.ActiveView().View(m, rightWidth, m.Height-4), rightWidth, m.Height-4)
 
 	// Combine panels
 	leftPanel := lipgloss.NewStyle().
@@ -71,97 +119,204 @@ func RenderView(m *models.Model) string {
 		BorderForeground(lipgloss.Color(ColorBorder)).
 		Render(right)
 
-	panels := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+}
+
+// spinnerFrames animate the status bar while operations run.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// renderOperations shows the oldest operation in flight with its elapsed
+// time, and how many more are running.
+func renderOperations(m *models.Model) string {
+	op := m.Operations[0]
+	elapsed := time.Since(op.Started).Truncate(time.Second)
+	text := fmt.Sprintf("%s %s... %s", spinnerFrames[m.SpinnerFrame%len(spinnerFrames)], op.Label, elapsed)
+	if more := len(m.Operations) - 1; more > 0 {
+		text += fmt.Sprintf(" (+%d more)", more)
+	}
+	if back := keyLabel(m.KeySettings().Views.Back); back != "" {
+		text += " • " + back + ": cancel"
+	}
+	return text
+}
+
+// openPortHint tells which keys open the selected port.
+func openPortHint(m *models.Model) string {
+	keys := m.KeySettings().Container.OpenPort
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = "'" + key + "'"
+	}
+	return "Press " + strings.Join(quoted, " or ") + " to open in browser"
+}
+
+// viewHints are the shortcuts of the current view for the status bar.
+func viewHints(m *models.Model) string {
+	kb := m.KeySettings()
+	nav, c, logs := kb.Navigation, kb.Container, kb.Logs
+	move := hint{key: keyPair(nav.Down, nav.Up), desc: "move"}
+	scroll := hint{key: keyPair(nav.Down, nav.Up), desc: "scroll"}
+	back := hint{key: keyLabel(kb.Views.Back), desc: "back"}
+	cmd := hint{key: keyLabel(kb.Commands.Enter), desc: "cmd"}
+	mark := hint{key: strings.Join(slices.DeleteFunc([]string{keyLabel(kb.Selection.Toggle), keyLabel(kb.Selection.MarkAll), keyLabel(kb.Selection.Invert)}, func(k string) bool { return k == "" }), "/"), desc: "mark"}
+	start, stop := hint{key: ":s", desc: "start", mutating: true}, hint{key: ":S", desc: "stop", mutating: true}
+	restart := hint{key: keyLabel(c.Restart), desc: "restart", mutating: true}
+	remove := hint{key: keyLabel(c.Delete), desc: "delete", mutating: true}
+
+	switch m.ViewMode {
+	case models.ViewLogs:
+		follow := "off"
+		if m.FollowingLogs {
+			follow = "on"
+		}
+		if m.LogProject != "" {
+			return hintLine(m, scroll, hint{key: keyLabel(logs.Search), desc: "search"}, hint{key: keyLabel(logs.Follow), desc: "follow(" + follow + ")"},
+				hint{key: keyLabel(logs.StreamFilter), desc: "streams(" + m.LogStreamFilter.String() + ")"}, hint{key: keyLabel(logs.CycleSource), desc: "cycle service"},
+				hint{key: ":svc <name>", desc: "toggle"}, back)
+		}
+		return hintLine(m, scroll, hint{key: keyLabel(logs.Search), desc: "search"}, hint{key: keyPair(logs.NextResult, logs.PrevResult), desc: "next/prev"},
+			hint{key: keyLabel(logs.Follow), desc: "follow(" + follow + ")"}, hint{key: keyLabel(logs.StreamFilter), desc: "streams(" + m.LogStreamFilter.String() + ")"},
+			hint{key: keyLabel(logs.LevelFilter), desc: "level(" + m.LogMinLevel.String() + ")"}, hint{key: keyLabel(logs.JSONView), desc: "json"},
+			hint{key: keyLabel(logs.Expand), desc: "expand"}, hint{key: keyLabel(logs.LoadOlder), desc: "older"}, back, cmd)
+	case models.ViewPorts:
+		return hintLine(m, hint{key: keyPair(nav.Down, nav.Up), desc: "select port"}, hint{key: strings.Join(c.OpenPort, "/"), desc: "open in browser"}, back, cmd)
+	case models.ViewEnv:
+		return hintLine(m, back, cmd)
+	case models.ViewStats:
+		return hintLine(m, hint{key: keyLabel(c.RefreshStats), desc: "reconnect"}, back, cmd)
+	case models.ViewInspect:
+		return hintLine(m, scroll, hint{key: keyPair(nav.Top, nav.Bottom), desc: "top/bottom"}, back, cmd)
+	case models.ViewDetails:
+		// Show detailed instructions for the details view
+		navigate := hint{key: switchLabel(m, 4), desc: "nav"}
+		switch {
+		case len(m.Items) == 0:
+			return hintLine(m, hint{key: switchLabel(m, 4), desc: "switch resource"}, hint{key: ":help", desc: "shortcuts"}, hint{key: ":q", desc: "quit"})
+		case m.NavMode == models.NavContainers && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer:
+			return hintLine(m, start, stop, restart, remove,
+				hint{key: keyLabel(kb.Selection.Toggle), desc: "mark"}, hint{key: keyLabel(c.Logs), desc: "logs"}, hint{key: keyLabel(c.Exec), desc: "exec", mutating: true},
+				hint{key: keyLabel(c.Ports), desc: "ports"}, hint{key: keyLabel(c.Env), desc: "env"}, hint{key: keyLabel(c.Stats), desc: "stats"},
+				hint{key: keyLabel(c.Inspect), desc: "inspect"}, cmd) + " • :help"
+		case m.NavMode == models.NavContainers:
+			return hintLine(m, navigate, move, hint{key: keyLabel(nav.ToggleExpand), desc: "expand"}, mark, start, stop,
+				restart, hint{key: keyLabel(c.Delete), desc: "del", mutating: true}, hint{key: keyLabel(c.Logs), desc: "logs"},
+				hint{key: keyLabel(c.Exec), desc: "exec", mutating: true}, cmd) + " • :help"
+		case m.NavMode == models.NavVolumes, m.NavMode == models.NavImages, m.NavMode == models.NavNetworks:
+			return hintLine(m, navigate, move, mark, remove, cmd) + " • :help"
+		case m.NavMode == models.NavTop:
+			return hintLine(m, hint{key: switchLabel(m, 5), desc: "nav"}, move, hint{key: keyLabel(kb.Top.Jump), desc: "open"},
+				hint{key: keyPair(kb.Top.SortPrev, kb.Top.SortNext), desc: "sort column"}, hint{key: keyLabel(kb.Top.SortReverse), desc: "reverse"}) +
+				" • :sort <col> • " + hintLine(m, hint{key: keyLabel(c.Logs), desc: "logs"}, hint{key: keyLabel(c.Stats), desc: "stats"}) + " • :help"
+		case m.NavMode == models.NavAlerts:
+			return hintLine(m, hint{key: switchLabel(m, 6), desc: "nav"}, move, hint{key: keyLabel(kb.Alerts.Jump), desc: "open container"},
+				hint{key: keyLabel(kb.Alerts.Clear), desc: "clear resolved"}, cmd) + " • :help"
+		case m.NavMode == models.NavEvents:
+			return hintLine(m, hint{key: switchLabel(m, 7), desc: "nav"}, move, hint{key: keyLabel(kb.Events.Jump), desc: "open resource"},
+				hint{key: keyLabel(kb.Events.CycleType), desc: "cycle type"}) + " • :events <filter> • :events since 1h • :help"
+		}
+		return ""
+	}
+	keys := switchKeys(m)
+	return hintLine(m, hint{key: keyLabel(keys[0]), desc: "containers"}, hint{key: keyLabel(keys[1]), desc: "volumes"}, hint{key: keyLabel(keys[2]), desc: "images"},
+		hint{key: keyLabel(keys[3]), desc: "networks"}, hint{key: keyPair(nav.Down, nav.Up), desc: "nav"}, cmd) + " • :help • :q: quit"
+}
+
+// confirmHints are the keys of a confirmation dialog.
+func confirmHints(m *models.Model) string {
+	return hintLine(m, hint{key: "y", desc: "confirm"}, hint{key: keyPair([]string{"n"}, m.KeySettings().Views.Back), desc: "cancel"})
+}
 
-	// Status bar
+// renderStatusBar shows the command/search prompt, the running operations,
+// the status message or the shortcuts of the current view.
+func renderStatusBar(m *models.Model) string {
 	var statusText string
 
 	// Priority 1: Command/search mode (highest priority)
-	if m.CommandMode {
+	if m.Input == models.InputCommand {
 		statusText = ":" + m.CommandInput + "█ • enter: execute • esc: cancel"
-	} else if m.SearchMode {
-		statusText = "?" + m.SearchQuery + "█ • enter: search • esc: cancel"
+	} else if m.Input == models.InputSearch {
+		statusText = "?" + m.SearchQuery + "█ • enter: search • /re/: regex • esc: cancel"
+	} else if m.Input == models.InputConfirm {
+		statusText = confirmHints(m)
+	} else if len(m.Operations) > 0 {
+		// Priority 2: Docker requests the user is waiting on
+		statusText = renderOperations(m)
 	} else if m.StatusMessage != "" {
-		// Priority 2: Status messages (but not while in command/search mode)
+		// Priority 3: Status messages (but not while in command/search mode)
 		statusText = m.StatusMessage
 	} else {
-		// Priority 3: Context-specific shortcuts
-		switch m.ViewMode {
-		case models.ViewLogs:
-			follow := "off"
-			if m.FollowingLogs {
-				follow = "on"
-			}
-			statusText = "j/k: scroll • g/G: top/bottom • ?: search • n/N: next/prev • f: follow(" + follow + ") • :noh: clear • esc: back • :: cmd"
-		case models.ViewPorts:
-			statusText = "j/k: select port • o/enter: open in browser • esc: back • :: cmd"
-		case models.ViewEnv:
-			statusText = "esc: back • :: cmd"
-		case models.ViewStats:
-			statusText = "t: refresh • esc: back • :: cmd"
-		case models.ViewInspect:
-			statusText = "j/k: scroll • g/G: top/bottom • esc: back • :: cmd"
-		case models.ViewDetails:
-			// Show detailed instructions for the details view
-			if len(m.Items) == 0 {
-				statusText = "1-4: switch resource • :help: shortcuts • :q: quit"
-			} else if m.NavMode == models.NavContainers && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
-				statusText = ":s: start • :S: stop • r: restart • d: delete • l: logs • e: exec • p: ports • v: env • t: stats • i: inspect • :: cmd • :help"
-			} else if m.NavMode == models.NavContainers {
-				statusText = "1-4: nav • j/k: move • space: expand • :s: start • :S: stop • r: restart • d: del • l: logs • e: exec • :: cmd • :help"
-			} else if m.NavMode == models.NavVolumes {
-				statusText = "1-4: nav • j/k: move • d: delete • :: cmd • :help"
-			} else if m.NavMode == models.NavImages {
-				statusText = "1-4: nav • j/k: move • d: delete • :: cmd • :help"
-			} else if m.NavMode == models.NavNetworks {
-				statusText = "1-4: nav • j/k: move • :: cmd • :help"
-			}
-		default:
-			statusText = "1: containers • 2: volumes • 3: images • 4: networks • j/k: nav • :: cmd • :help • :q: quit"
-		}
+		// Priority 4: Context-specific shortcuts
+		statusText = viewHints(m)
 	}
 
-	statusBar := lipgloss.NewStyle().
+	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color(ColorMuted)).
-		Render(statusText)
-
-	return lipgloss.JoinVertical(lipgloss.Left, header, panels, statusBar)
+		Render(ansi.Truncate(statusText, m.Width, "…"))
 }
 
 func RenderList(m *models.Model, width, height int) string {
 	var s strings.Builder
-	cfg := uiConfig(m)
+	cfg := m.UISettings()
 
 	// Show appropriate title based on navigation mode
 	var titleText string
 	var emptyText string
 	switch m.NavMode {
 	case models.NavContainers:
-		titleText = "Containers [1]"
+		titleText = "Containers" + titleKey(m, models.NavContainers)
 		emptyText = "No containers found"
 	case models.NavVolumes:
-		titleText = "Volumes [2]"
+		titleText = "Volumes" + titleKey(m, models.NavVolumes)
 		emptyText = "No volumes found"
 	case models.NavImages:
-		titleText = "Images [3]"
+		titleText = "Images" + titleKey(m, models.NavImages)
 		emptyText = "No images found"
 	case models.NavNetworks:
-		titleText = "Networks [4]"
+		titleText = "Networks" + titleKey(m, models.NavNetworks)
 		emptyText = "No networks found"
+	case models.NavTop:
+		titleText = "Top" + titleKey(m, models.NavTop)
+		emptyText = "No running containers"
+	case models.NavAlerts:
+		titleText = "Alerts" + titleKey(m, models.NavAlerts)
+		emptyText = "No alerts"
+	case models.NavEvents:
+		titleText = "Events" + titleKey(m, models.NavEvents)
+		emptyText = "No events"
 	}
 
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color(ColorTitle)).
 		Render(titleText)
+	if marked := len(m.MarkedItems()); marked > 0 {
+		title += lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorWarning)).
+			Render(fmt.Sprintf(" %s %d marked", IconMarked, marked))
+	}
 	s.WriteString(title + "\n\n")
 
+	// A failed load is shown in place of the list, or above what was loaded before
+	resource, fromDocker := models.NavResource(m.NavMode)
+	if errText := m.LoadErrors[resource]; fromDocker && errText != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError))
+		text := fmt.Sprintf("%s Failed to load %s: %s", IconAlert, resource, errText)
+		for _, line := range loadErrorLines(text, width) {
+			s.WriteString(errStyle.Render(line) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
 	if len(m.Items) == 0 {
 		emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+		if fromDocker && m.Loading[resource] {
+			s.WriteString(emptyStyle.Render(fmt.Sprintf("Loading %s...", resource)))
+			return s.String()
+		}
 		s.WriteString(emptyStyle.Render(emptyText) + "\n\n")
-		s.WriteString(emptyStyle.Render("Try:\n"))
-		s.WriteString(emptyStyle.Render("• start Docker daemon\n"))
-		s.WriteString(emptyStyle.Render("• switch resources with 1-4\n"))
+		s.WriteString(emptyStyle.Render("Try:") + "\n")
+		s.WriteString(emptyStyle.Render("• start Docker daemon") + "\n")
+		s.WriteString(emptyStyle.Render("• switch resources with "+switchLabel(m, 4)) + "\n")
 		s.WriteString(emptyStyle.Render("• open :help for all commands"))
 		return s.String()
 	}
@@ -187,6 +342,13 @@ func RenderList(m *models.Model, width, height int) string {
 		if i == m.Cursor {
 			cursor = "> "
 		}
+		// Marked rows carry the mark next to the cursor
+		if m.IsMarked(item) {
+			cursor = cursor[:1] + lipgloss.NewStyle().
+				Foreground(lipgloss.Color(ColorWarning)).
+				Bold(true).
+				Render(IconMarked)
+		}
 
 		if item.IsProject {
 			icon := IconCollapsed + " "
@@ -293,6 +455,25 @@ func RenderList(m *models.Model, width, height int) string {
 
 			line := fmt.Sprintf("%s%s %s", cursor, networkIcon, item.Network.Name)
 
+			if i == m.Cursor {
+				line = lipgloss.NewStyle().
+					Background(lipgloss.Color(ColorHighlight)).
+					Bold(true).
+					Render(line)
+			}
+			s.WriteString(line + "\n")
+
+		} else if item.IsAlert {
+			alert := item.Alert
+			icon := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError)).Render(IconAlert)
+			textStyle := lipgloss.NewStyle()
+			if !alert.Active() {
+				icon = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(IconCleared)
+				textStyle = textStyle.Foreground(lipgloss.Color(ColorMuted))
+			}
+
+			line := fmt.Sprintf("%s%s %s", cursor, icon, textStyle.Render(fmt.Sprintf("%s %s %.0f%%", alert.ContainerName, alert.Metric, alert.Value)))
+
 			if i == m.Cursor {
 				line = lipgloss.NewStyle().
 					Background(lipgloss.Color(ColorHighlight)).
@@ -315,7 +496,7 @@ func RenderList(m *models.Model, width, height int) string {
 
 func RenderDetails(m *models.Model, width, height int) string {
 	var s strings.Builder
-	cfg := uiConfig(m)
+	cfg := m.UISettings()
 
 	title := lipgloss.NewStyle().
 		Bold(true).
@@ -327,16 +508,21 @@ func RenderDetails(m *models.Model, width, height int) string {
 	if m.Cursor < 0 || m.Cursor >= len(m.Items) {
 		emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
 		s.WriteString(emptyStyle.Render("No selection") + "\n\n")
-		s.WriteString(emptyStyle.Render("Quick start:\n"))
-		s.WriteString(emptyStyle.Render("• 1-4 to switch resources\n"))
-		s.WriteString(emptyStyle.Render("• j/k to move cursor\n"))
+		s.WriteString(emptyStyle.Render("Quick start:") + "\n")
+		nav := m.KeySettings().Navigation
+		s.WriteString(emptyStyle.Render("• "+switchLabel(m, 4)+" to switch resources") + "\n")
+		s.WriteString(emptyStyle.Render("• "+keyPair(nav.Down, nav.Up)+" to move cursor") + "\n")
 		s.WriteString(emptyStyle.Render("• :help to open full help"))
-		return s.String()
+		return fit(s.String(), width, height)
 	}
 
 	item := m.Items[m.Cursor]
 
 	if item.IsProject {
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorMuted)).
+			Render(actionsLine(m, hint{key: keyLabel(m.KeySettings().Container.Logs), desc: "project logs"}, hint{key: keyLabel(m.KeySettings().Navigation.ToggleExpand), desc: "expand"})) + "\n\n")
+
 		s.WriteString(renderLabel("Project") + item.Project.Name + "\n")
 		s.WriteString(renderLabel("Containers") + fmt.Sprintf("%d", len(item.Project.Containers)) + "\n\n")
 
@@ -359,13 +545,18 @@ func RenderDetails(m *models.Model, width, height int) string {
 				Render(GetContainerStatusIcon(c.State))
 			s.WriteString(fmt.Sprintf("  %s %s\n", status, c.Name))
 		}
+		s.WriteString("\n")
+		s.WriteString(RenderProjectStats(m, item.Project, width-4))
 
 	} else if item.IsContainer {
 		c := item.Container
 
+		keys := m.KeySettings().Container
+		actions := actionsLine(m, hint{key: keyLabel(keys.Logs), desc: "logs"}, hint{key: keyLabel(keys.Exec), desc: "exec", mutating: true}, hint{key: keyLabel(keys.Ports), desc: "ports"},
+			hint{key: keyLabel(keys.Env), desc: "env"}, hint{key: keyLabel(keys.Stats), desc: "stats"}, hint{key: keyLabel(keys.Inspect), desc: "inspect"})
 		s.WriteString(lipgloss.NewStyle().
 			Foreground(lipgloss.Color(ColorMuted)).
-			Render("Actions: l logs • e exec • p ports • v env • t stats • i inspect") + "\n\n")
+			Render(actions) + "\n\n")
 
 		s.WriteString(renderLabel("Name") + c.Name + "\n")
 
@@ -466,9 +657,49 @@ func RenderDetails(m *models.Model, width, height int) string {
 				fmt.Fprintf(&s, "  %s=%s\n", k, val)
 			}
 		}
+
+	} else if item.IsAlert {
+		alert := item.Alert
+
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorMuted)).
+			Render(actionsLine(m, hint{key: keyLabel(m.KeySettings().Alerts.Jump), desc: "open container"}, hint{key: keyLabel(m.KeySettings().Alerts.Clear), desc: "clear resolved"})) + "\n\n")
+
+		state := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError)).Render("Firing")
+		if !alert.Active() {
+			state = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSuccess)).Render("Resolved")
+		}
+		s.WriteString(renderLabel("State") + state + "\n")
+		s.WriteString(renderLabel("Container") + alert.ContainerName + "\n")
+		s.WriteString(renderLabel("ID") + alert.ContainerID + "\n\n")
+
+		s.WriteString(renderLabel("Metric") + string(alert.Metric) + "\n")
+		s.WriteString(renderLabel("Threshold") + fmt.Sprintf("%.1f%%", alert.Threshold) + "\n")
+		s.WriteString(renderLabel("Value") + fmt.Sprintf("%.1f%%", alert.Value) + "\n")
+		s.WriteString(renderLabel("Peak") + fmt.Sprintf("%.1f%%", alert.Peak) + "\n\n")
+
+		s.WriteString(renderLabel("Exceeded") + formatTimeAgo(alert.Since) + "\n")
+		s.WriteString(renderLabel("Fired") + formatTimeAgo(alert.FiredAt) + "\n")
+		if !alert.Active() {
+			s.WriteString(renderLabel("Resolved") + formatTimeAgo(alert.ResolvedAt) + "\n")
+		}
+
+		if alert.Rule < len(m.AlertRules) {
+			rule := m.AlertRules[alert.Rule]
+			s.WriteString("\n" + renderLabel("Rule") + fmt.Sprintf("container %q for %s", rule.Container, rule.For) + "\n")
+			if rule.Command != "" {
+				s.WriteString(renderLabel("Command") + rule.Command + "\n")
+			}
+			if rule.Webhook != "" {
+				s.WriteString(renderLabel("Webhook") + rule.Webhook + "\n")
+			}
+		}
+		if alert.NotifyErr != "" {
+			s.WriteString(renderLabel("Hook error") + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError)).Render(alert.NotifyErr) + "\n")
+		}
 	}
 
-	return s.String()
+	return fit(s.String(), width, height)
 }
 
 func RenderPorts(m *models.Model, width, height int) string {
@@ -520,7 +751,7 @@ func RenderPorts(m *models.Model, width, height int) string {
 		}
 	}
 
-	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render("Press 'o' or 'enter' to open in browser"))
+	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(openPortHint(m)))
 
 	return s.String()
 }
@@ -572,7 +803,7 @@ func RenderEnv(m *models.Model, width, height int) string {
 
 func RenderLogs(m *models.Model, width, height int) string {
 	var s strings.Builder
-	cfg := uiConfig(m)
+	cfg := m.UISettings()
 
 	containerName := "container"
 	if m.Cursor >= 0 && m.Cursor < len(m.Items) && m.Items[m.Cursor].IsContainer {
@@ -582,15 +813,76 @@ func RenderLogs(m *models.Model, width, height int) string {
 	if m.FollowingLogs {
 		mode = "following"
 	}
-	s.WriteString(renderPaneHeader("Logs", fmt.Sprintf("%s • %d lines • %s", containerName, len(m.Logs), mode)))
+	stderrCount := 0
+	for _, entry := range m.Logs {
+		if entry.Stream == models.StreamStderr {
+			stderrCount++
+		}
+	}
+	ttyCount := 0
+	for _, t := range m.LogTargets {
+		if t.TTY {
+			ttyCount++
+		}
+	}
+	streams := "streams: " + m.LogStreamFilter.String()
+	if ttyCount > 0 && ttyCount == len(m.LogTargets) {
+		streams = "tty (stdout/stderr merged)"
+	}
+
+	// Project logs merge several containers; each line gets a coloured service prefix.
+	title := "Logs"
+	sourceWidth := 0
+	sourceColors := make(map[string]string)
+	if m.LogProject != "" {
+		title = "Project Logs"
+		hidden := 0
+		for i, t := range m.LogTargets {
+			sourceColors[t.Source] = ServiceColors[i%len(ServiceColors)]
+			sourceWidth = max(sourceWidth, len(t.Source))
+			if m.HiddenSources[t.Source] {
+				hidden++
+			}
+		}
+		containerName = fmt.Sprintf("%s • %d/%d services", m.LogProject, len(m.LogTargets)-hidden, len(m.LogTargets))
+	}
+	subtitle := fmt.Sprintf("%s • %d lines • %d stderr • %s • %s", containerName, len(m.Logs), stderrCount, mode, streams)
+	if m.LogRangeSince != "" {
+		subtitle += " • since " + m.LogRangeSince
+	}
+	if m.LogRangeUntil != "" {
+		subtitle += " • until " + m.LogRangeUntil
+	}
+	subtitle += " • " + formatLevelCounts(m.Logs)
+	if m.LogMinLevel != models.LevelUnknown {
+		subtitle += " • level ≥ " + m.LogMinLevel.String()
+	}
+	if len(m.LogFieldFilters) > 0 {
+		terms := make([]string, len(m.LogFieldFilters))
+		for i, f := range m.LogFieldFilters {
+			terms[i] = f.String()
+		}
+		subtitle += " • filter " + strings.Join(terms, " ")
+	}
+	s.WriteString(renderPaneHeader(title, subtitle))
 
-	if len(m.Logs) == 0 {
+	if len(m.VisibleLogs) == 0 {
+		if len(m.Logs) > 0 {
+			s.WriteString("No lines match the current filters")
+			return s.String()
+		}
 		s.WriteString("No logs available")
 		return s.String()
 	}
 
 	if m.SearchQuery != "" {
 		searchStatus := fmt.Sprintf("Search: %q (%d matches)", m.SearchQuery, len(m.SearchResults))
+		if m.SearchSmartCase {
+			searchStatus += " • smart case"
+		}
+		if m.SearchFilter {
+			searchStatus += fmt.Sprintf(" • filter ±%d", m.SearchContext)
+		}
 		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(searchStatus) + "\n\n")
 	}
 
@@ -599,19 +891,26 @@ func RenderLogs(m *models.Model, width, height int) string {
 
 	// Ensure scroll position is valid
 	scrollPos := m.LogScroll
-	if scrollPos >= len(m.Logs) {
-		scrollPos = len(m.Logs) - 1
+	if scrollPos >= len(m.VisibleLogs) {
+		scrollPos = len(m.VisibleLogs) - 1
 	}
 	if scrollPos < 0 {
 		scrollPos = 0
 	}
 
+	// The expanded tree of the selected line takes room from the window
+	var detail []string
+	if m.LogDetail {
+		detail = logDetailLines(m.Logs[m.VisibleLogs[scrollPos]], maxVisible/2)
+		maxVisible = max(maxVisible-len(detail), 1)
+	}
+
 	// Calculate start position to keep cursor centered
 	start := max(scrollPos-maxVisible/2, 0)
 
 	end := start + maxVisible
-	if end > len(m.Logs) {
-		end = len(m.Logs)
+	if end > len(m.VisibleLogs) {
+		end = len(m.VisibleLogs)
 		// Adjust start if we're at the end
 		start = max(end-maxVisible, 0)
 	}
@@ -622,14 +921,72 @@ func RenderLogs(m *models.Model, width, height int) string {
 		searchResultMap[idx] = true
 	}
 
+	stderrStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorError))
+
+	if start == 0 {
+		marker := "↑ load older lines"
+		if key := keyLabel(m.KeySettings().Logs.LoadOlder); key != "" {
+			marker = "↑ " + key + ": load older lines"
+		}
+		if m.LoadingOlder {
+			marker = "Loading older lines..."
+		} else if m.LogsAtStart {
+			marker = "— beginning of log —"
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(marker) + "\n")
+		maxVisible--
+		end = min(end, start+max(maxVisible, 1))
+	}
+
+	jsonKeys := m.LogsSettings().JSONFields
+	var jsonWidths []int
+	if m.LogJSONView {
+		jsonWidths = jsonColumnWidths(m, start, end, jsonKeys)
+	}
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorInfo))
+
+	contextStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+
 	// Show logs in window
 	for i := start; i < end; i++ {
-		line := m.Logs[i]
+		// Filter mode separates groups of matches that are not adjacent
+		if i > start && m.LogContextBreaks[i] {
+			s.WriteString(contextStyle.Render("--") + "\n")
+			if end-1 > max(i, scrollPos) {
+				end--
+			}
+		}
+
+		idx := m.VisibleLogs[i]
+		entry := m.Logs[idx]
+		line := entry.Line
+		if m.LogJSONView {
+			if fields, ok := entry.JSON(); ok {
+				line = formatJSONColumns(entry, fields, jsonKeys, jsonWidths)
+			}
+		}
 		gutter := ""
 		if cfg.ShowLineNumbers {
 			gutter = lipgloss.NewStyle().
 				Foreground(lipgloss.Color(ColorMuted)).
-				Render(fmt.Sprintf("%4d ", i+1))
+				Render(fmt.Sprintf("%4d ", idx+1))
+		}
+
+		prefix := ""
+		if sourceWidth > 0 {
+			prefix = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(sourceColors[entry.Source])).
+				Render(fmt.Sprintf("%-*s | ", sourceWidth, entry.Source))
+		}
+
+		// With stderr in the buffer, a marker column keeps it distinct from
+		// stdout even when the text is coloured by level.
+		marker := ""
+		if stderrCount > 0 {
+			marker = " "
+			if entry.Stream == models.StreamStderr {
+				marker = stderrStyle.Render("▌")
+			}
 		}
 
 		// Truncate long lines to fit width
@@ -637,6 +994,12 @@ func RenderLogs(m *models.Model, width, height int) string {
 		if cfg.ShowLineNumbers {
 			maxLineWidth = width - 9
 		}
+		if sourceWidth > 0 {
+			maxLineWidth -= sourceWidth + 3
+		}
+		if marker != "" {
+			maxLineWidth--
+		}
 		if maxLineWidth < 12 {
 			maxLineWidth = 12
 		}
@@ -644,26 +1007,48 @@ func RenderLogs(m *models.Model, width, height int) string {
 			line = line[:maxLineWidth-3] + "..."
 		}
 
+		// Lines are coloured by level; unclassified stderr lines use the error colour
+		base := lipgloss.NewStyle()
+		if color := levelColor(entry.Level); color != "" {
+			base = base.Foreground(lipgloss.Color(color))
+		} else if entry.Stream == models.StreamStderr {
+			base = stderrStyle
+		}
+
 		// Highlight search matches in the line
-		if m.SearchQuery != "" && searchResultMap[i] {
-			line = highlightSearchTerm(line, m.SearchQuery)
+		if m.SearchRegex != nil && searchResultMap[i] {
+			line = highlightSearchTerm(line, m.SearchRegex, base)
+		} else if m.SearchFilter && m.SearchRegex != nil {
+			// Context lines around matches are dimmed, like grep -C
+			line = contextStyle.Render(line)
+		} else {
+			line = base.Render(line)
 		}
 
 		if i == scrollPos {
 			// Highlight current line
 			line = lipgloss.NewStyle().
 				Background(lipgloss.Color(ColorHighlight)).
-				Render(gutter + line)
+				Render(gutter + marker + prefix + line)
 		} else {
-			line = gutter + line
+			line = gutter + marker + prefix + line
 		}
 		s.WriteString(line + "\n")
+
+		if i == scrollPos {
+			for _, d := range detail {
+				if len(d) > width-2 {
+					d = d[:max(width-5, 1)] + "..."
+				}
+				s.WriteString(detailStyle.Render(d) + "\n")
+			}
+		}
 	}
 
 	// Show position indicator
 	s.WriteString("\n")
-	progress := int(float64(scrollPos+1) / float64(len(m.Logs)) * 100)
-	indicator := fmt.Sprintf("Line %d/%d (%d-%d visible, %d%%)", scrollPos+1, len(m.Logs), start+1, end, progress)
+	progress := int(float64(scrollPos+1) / float64(len(m.VisibleLogs)) * 100)
+	indicator := fmt.Sprintf("Line %d/%d (%d-%d visible, %d%%)", scrollPos+1, len(m.VisibleLogs), start+1, end, progress)
 	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(indicator))
 
 	return s.String()
@@ -693,49 +1078,56 @@ func formatTimeAgo(t time.Time) string {
 	}
 }
 
-func highlightSearchTerm(line, query string) string {
-	if query == "" {
-		return line
+// highlightSearchTerm highlights every match of re in line and renders the
+// text between matches with base. When re has capture groups, only the
+// captured text is highlighted.
+func highlightSearchTerm(line string, re *regexp.Regexp, base lipgloss.Style) string {
+	spans := searchSpans(line, re)
+	if len(spans) == 0 {
+		return base.Render(line)
 	}
 
-	lowerLine := strings.ToLower(line)
-	lowerQuery := strings.ToLower(query)
+	highlight := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorSearchHighlightFG)).
+		Background(lipgloss.Color(ColorSearchHighlightBG))
 
-	// Find the position of the query in the line
-	idx := strings.Index(lowerLine, lowerQuery)
-	if idx == -1 {
-		return line
-	}
-
-	// Highlight all occurrences
 	var result strings.Builder
 	lastIdx := 0
-
-	for idx != -1 {
-		// Add text before match
-		result.WriteString(line[lastIdx:idx])
-
-		// Add highlighted match
-		match := line[idx : idx+len(query)]
-		highlighted := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(ColorSearchHighlightFG)).
-			Background(lipgloss.Color(ColorSearchHighlightBG)).
-			Render(match)
-		result.WriteString(highlighted)
-
-		lastIdx = idx + len(query)
-		idx = strings.Index(lowerLine[lastIdx:], lowerQuery)
-		if idx != -1 {
-			idx += lastIdx
-		}
+	for _, span := range spans {
+		result.WriteString(base.Render(line[lastIdx:span[0]]))
+		result.WriteString(highlight.Render(line[span[0]:span[1]]))
+		lastIdx = span[1]
 	}
-
-	// Add remaining text
-	result.WriteString(line[lastIdx:])
+	result.WriteString(base.Render(line[lastIdx:]))
 
 	return result.String()
 }
 
+// searchSpans returns the sorted, non-overlapping [start, end) byte ranges to
+// highlight: each capture group of every match, or whole matches when the
+// pattern has no groups. Empty matches are skipped.
+func searchSpans(line string, re *regexp.Regexp) [][2]int {
+	var spans [][2]int
+	for _, match := range re.FindAllStringSubmatchIndex(line, -1) {
+		groups := match[2:]
+		if len(groups) == 0 {
+			groups = match[:2]
+		}
+		for g := 0; g+1 < len(groups); g += 2 {
+			start, end := groups[g], groups[g+1]
+			if start < 0 || start == end {
+				continue
+			}
+			// Nested groups overlap their parent; keep the first span only
+			if len(spans) > 0 && start < spans[len(spans)-1][1] {
+				continue
+			}
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	return spans
+}
+
 func RenderStats(m *models.Model, width, height int) string {
 	var s strings.Builder
 
@@ -750,25 +1142,57 @@ func RenderStats(m *models.Model, width, height int) string {
 		return s.String()
 	}
 
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSuccess)).Bold(true).Render("Runtime") + "\n")
-	s.WriteString(renderMetricRow("CPU", m.Stats.CPUPerc))
-	s.WriteString(renderMetricRow("PIDs", m.Stats.PIDs))
-	s.WriteString("\n")
-
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSuccess)).Bold(true).Render("Memory") + "\n")
-	s.WriteString(renderMetricRow("Usage", m.Stats.MemUsage))
-	s.WriteString(renderMetricRow("Percent", m.Stats.MemPerc))
-	s.WriteString("\n")
-
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSuccess)).Bold(true).Render("I/O") + "\n")
-	s.WriteString(renderMetricRow("Network", m.Stats.NetIO))
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render("  rx/tx") + "\n")
-	s.WriteString(renderMetricRow("Block", m.Stats.BlockIO))
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render("  read/write") + "\n")
+	stats := m.Stats
+	history := m.StatsHistory
+	layout := fitStats(height)
+	rows := layout.chartRows
+	gap := ""
+	if layout.spacing {
+		gap = "\n"
+	}
+	percent := func(v float64) string { return fmt.Sprintf("%.2f%%", v) }
+	bytes := func(v float64) string { return formatBytes(uint64(v)) }
+	section := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSuccess)).Bold(true)
+
+	s.WriteString(section.Render("Runtime") + "\n")
+	s.WriteString(renderStatsMetric("CPU", percent(stats.CPUPercent), history,
+		func(c models.ContainerStats) float64 { return c.CPUPercent }, percent, ColorInfo, width, rows))
+	if layout.pids {
+		s.WriteString(renderMetricRow("PIDs", fmt.Sprintf("%d", stats.PIDs)))
+	}
+	s.WriteString(gap)
+
+	s.WriteString(section.Render("Memory") + "\n")
+	memory := fmt.Sprintf("%s / %s (%.2f%%)", formatBytes(stats.MemUsage), formatBytes(stats.MemLimit), stats.MemPercent)
+	s.WriteString(renderStatsMetric("Usage", memory, history,
+		func(c models.ContainerStats) float64 { return float64(c.MemUsage) }, bytes, ColorSuccess, width, rows))
+	s.WriteString(gap)
+
+	s.WriteString(section.Render("Network") + "\n")
+	s.WriteString(renderStatsMetric("rx", formatRate(stats.NetRxRate)+" • "+formatBytes(stats.NetRx), history,
+		func(c models.ContainerStats) float64 { return c.NetRxRate }, formatRate, ColorImage, width, rows))
+	s.WriteString(renderStatsMetric("tx", formatRate(stats.NetTxRate)+" • "+formatBytes(stats.NetTx), history,
+		func(c models.ContainerStats) float64 { return c.NetTxRate }, formatRate, ColorNetwork, width, rows))
+
+	if layout.blockIO {
+		s.WriteString(gap)
+		s.WriteString(section.Render("Block I/O") + "\n")
+		s.WriteString(renderStatsMetric("read", formatRate(stats.BlockReadRate)+" • "+formatBytes(stats.BlockRead), history,
+			func(c models.ContainerStats) float64 { return c.BlockReadRate }, formatRate, ColorVolume, width, rows))
+		s.WriteString(renderStatsMetric("write", formatRate(stats.BlockWriteRate)+" • "+formatBytes(stats.BlockWrite), history,
+			func(c models.ContainerStats) float64 { return c.BlockWriteRate }, formatRate, ColorDefault, width, rows))
+	}
 
-	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render("Press 't' to refresh stats"))
+	status := fmt.Sprintf("Live • %d samples", len(history))
+	if m.StatsStream == nil {
+		status = fmt.Sprintf("Stream stopped • %d samples", len(history))
+		if key := keyLabel(m.KeySettings().Container.RefreshStats); key != "" {
+			status += fmt.Sprintf(" • press '%s' to reconnect", key)
+		}
+	}
+	s.WriteString(gap + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Render(status))
 
-	return s.String()
+	return clip(s.String(), width, height)
 }
 
 func formatBytes(bytes uint64) string {
@@ -786,7 +1210,7 @@ func formatBytes(bytes uint64) string {
 
 func RenderInspect(m *models.Model, width, height int) string {
 	var s strings.Builder
-	cfg := uiConfig(m)
+	cfg := m.UISettings()
 
 	if m.InspectData == "" {
 		s.WriteString(renderPaneHeader("Container Inspect (JSON)", "No inspect data loaded"))
@@ -832,6 +1256,7 @@ func RenderInspect(m *models.Model, width, height int) string {
 				Foreground(lipgloss.Color(ColorMuted)).
 				Render(fmt.Sprintf("%4d ", i+1))
 		}
+
 		// Truncate long lines to fit width
 		maxLineWidth := width - 4
 		if cfg.ShowLineNumbers {
@@ -866,7 +1291,7 @@ func RenderInspect(m *models.Model, width, height int) string {
 
 func RenderHeader(m *models.Model, width int) string {
 	var running, stopped, total int
-	cfg := uiConfig(m)
+	cfg := m.UISettings()
 
 	// Count containers by state
 	for _, c := range m.Containers {
@@ -901,20 +1326,67 @@ func RenderHeader(m *models.Model, width int) string {
 	resourceStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
 
 	// Combine all parts
-	left := lipgloss.JoinHorizontal(lipgloss.Left, title, "  ", stats)
+	compact := lipgloss.JoinHorizontal(lipgloss.Left, title, "  ", stats)
+	left := compact
 	if cfg.ShowHeaderContext {
 		context := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(ColorMuted)).
 			Render(fmt.Sprintf("[%s • %s]", navModeLabel(m.NavMode), viewModeLabel(m.ViewMode)))
 		left = lipgloss.JoinHorizontal(lipgloss.Left, title, " ", context, "  ", stats)
 	}
+	// Badges go in front of the resource counts. In a narrow terminal the
+	// counts give way first, then the view context
 	right := resourceStyle.Render(resources)
+	badges := ""
+	if m.Context != "" {
+		label := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorInfo)).
+			Render(IconContext + " " + m.Context)
+		badges = label + "  " + badges
+	}
+	if m.Disconnected {
+		state := "disconnected"
+		if m.Reconnecting {
+			state = "reconnecting"
+		}
+		badge := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorError)).
+			Bold(true).
+			Render(fmt.Sprintf("%s %s", IconAlert, state))
+		badges = badge + "  " + badges
+	}
+	if m.ReadOnly {
+		badge := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorWarning)).
+			Bold(true).
+			Render(IconReadOnly + " read-only")
+		badges = badge + "  " + badges
+	}
+	if active := models.ActiveAlerts(m); active > 0 {
+		label := "alerts"
+		if active == 1 {
+			label = "alert"
+		}
+		badge := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorError)).
+			Bold(true).
+			Render(fmt.Sprintf("%s %d %s [6]", IconAlert, active, label))
+		badges = badge + "  " + badges
+	}
+	right = badges + right
+	if lipgloss.Width(left)+lipgloss.Width(right)+4 > width {
+		right = strings.TrimSuffix(badges, "  ")
+	}
+	if lipgloss.Width(left)+lipgloss.Width(right)+4 > width {
+		left = compact
+	}
 
-	// Calculate spacing
-	spacerWidth := max(width-lipgloss.Width(left)-lipgloss.Width(right)-4, 0)
+	// Calculate spacing, keeping left and right apart
+	spacerWidth := max(width-lipgloss.Width(left)-lipgloss.Width(right)-2, 2)
 	spacer := strings.Repeat(" ", spacerWidth)
 
-	header := lipgloss.JoinHorizontal(lipgloss.Left, " ", left, spacer, right, " ")
+	// Whatever still does not fit is cut off rather than wrapped
+	header := ansi.Truncate(lipgloss.JoinHorizontal(lipgloss.Left, " ", left, spacer, right, " "), max(width, 0), "…")
 
 	return lipgloss.NewStyle().
 		Background(lipgloss.Color(ColorBackground)).
@@ -933,6 +1405,12 @@ func navModeLabel(mode models.NavigationMode) string {
 		return "images"
 	case models.NavNetworks:
 		return "networks"
+	case models.NavTop:
+		return "top"
+	case models.NavAlerts:
+		return "alerts"
+	case models.NavEvents:
+		return "events"
 	default:
 		return "unknown"
 	}
@@ -959,70 +1437,200 @@ func viewModeLabel(mode models.ViewMode) string {
 	}
 }
 
+// helpRows is how many lines of help text the help box shows; padding and
+// the footer below them take the rest.
+func helpRows(height int) int {
+	return max(height-6, 1)
+}
+
+// helpLines is the help text wrapped to the inside of the help box.
+func helpLines(m *models.Model, width int) []string {
+	return strings.Split(ansi.Wrap(helpText(m), max(width-8, 1), ""), "\n")
+}
+
+func (Renderer) HelpScrollMax(m *models.Model) int {
+	return max(len(helpLines(m, m.Width))-helpRows(m.Height), 0)
+}
+
+// RenderHelp shows the help text in a box over the whole screen, scrolled
+// to m.HelpScroll when it does not fit.
 func RenderHelp(m *models.Model, width, height int) string {
+	lines := helpLines(m, width)
+	rows := helpRows(height)
+	first := min(m.HelpScroll, max(len(lines)-rows, 0))
+	shown := lines[first:min(first+rows, len(lines))]
+
+	kb := m.KeySettings()
+	footer := "Press " + keyLabel(kb.Views.Back) + " to close this help"
+	if len(lines) > rows {
+		footer = fmt.Sprintf("Lines %d-%d of %d • ", first+1, first+len(shown), len(lines)) +
+			hintLine(m, hint{key: keyPair(kb.Navigation.Down, kb.Navigation.Up), desc: "scroll"}, hint{key: keyPair(kb.Navigation.Top, kb.Navigation.Bottom), desc: "top/bottom"}, hint{key: keyLabel(kb.Views.Back), desc: "close"})
+	}
+	footer = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorMuted)).
+		Italic(true).
+		Render(ansi.Truncate(footer, max(width-8, 0), "…"))
+	helpContent := strings.Join(shown, "\n") + "\n\n" + footer
+
+	// Create a centered box for the help content
+	helpBox := lipgloss.NewStyle().
+		Width(width-4).
+		Height(height-2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(ColorInfo)).
+		Padding(1, 2).
+		Render(helpContent)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, helpBox)
+}
+
+// helpText is the keyboard and command reference.
+func helpText(m *models.Model) string {
 	var s strings.Builder
 
-	s.WriteString(renderPaneHeader("GDocker Help", "Keyboard and command reference"))
+	subtitle := "Keyboard and command reference"
+	if m.ReadOnly {
+		subtitle = "Read-only mode: actions that change Docker are hidden"
+	}
+	s.WriteString(renderPaneHeader("GDocker Help", subtitle))
 
+	kb := m.KeySettings()
+	nav, c, logs := kb.Navigation, kb.Container, kb.Logs
+	switches := switchKeys(m)
 	s.WriteString(renderHelpSection("Navigation", []helpEntry{
-		{key: "1-4", desc: "Switch between containers, volumes, images, networks"},
-		{key: "j/k, ↓/↑", desc: "Move cursor up/down"},
-		{key: "g/G", desc: "Jump to top/bottom"},
-		{key: "space/enter", desc: "Toggle project expansion"},
+		{key: switchLabel(m, 4), desc: "Switch between containers, volumes, images, networks"},
+		{key: keyPair(nav.Down, nav.Up) + ", ↓/↑", desc: "Move cursor up/down"},
+		{key: keyPair(nav.Top, nav.Bottom), desc: "Jump to top/bottom"},
+		{key: strings.ReplaceAll(strings.Join(nav.ToggleExpand, "/"), " ", "space"), desc: "Toggle project expansion"},
+		{key: keyLabel(switches[models.NavTop]), desc: "Top: resource usage of running containers"},
+		{key: keyLabel(switches[models.NavAlerts]), desc: "Alerts fired by the alert rules"},
+		{key: keyLabel(switches[models.NavEvents]), desc: "Events: timeline of Docker daemon events"},
+		{key: keyLabel(kb.Selection.Toggle), desc: "Mark/unmark row (a project marks its containers)"},
+		{key: strings.ReplaceAll(keyPair(kb.Selection.MarkAll, kb.Selection.Invert), "/", " / "), desc: "Mark all / invert marks; " + keyLabel(kb.Views.Back) + " clears them"},
 	}))
 	s.WriteString("\n")
 
-	s.WriteString(renderHelpSection("Container Actions", []helpEntry{
-		{key: ":s / :S", desc: "Start/stop container"},
-		{key: "r", desc: "Restart container"},
-		{key: "d", desc: "Delete container/volume/image"},
-		{key: "l", desc: "View logs"},
-		{key: "e", desc: "Execute shell (docker exec)"},
-		{key: "p", desc: "View port mappings"},
-		{key: "v", desc: "View environment variables"},
-		{key: "t", desc: "View/refresh stats"},
-		{key: "i", desc: "View inspect (JSON)"},
+	s.WriteString(renderHelpSection("Top View", []helpEntry{
+		{key: keyLabel(kb.Top.Jump), desc: "Open the container in the containers list"},
+		{key: strings.ReplaceAll(keyPair(kb.Top.SortPrev, kb.Top.SortNext), "/", " / "), desc: "Sort by previous/next column"},
+		{key: keyLabel(kb.Top.SortReverse), desc: "Reverse sort order"},
+		{key: ":sort <col>", desc: "Sort by name, cpu, mem, rx, tx, block or pids"},
 	}))
 	s.WriteString("\n")
 
+	s.WriteString(renderHelpSection("Alerts View", []helpEntry{
+		{key: keyLabel(kb.Alerts.Jump), desc: "Open the alert's container in the containers list"},
+		{key: keyLabel(kb.Alerts.Clear), desc: "Clear resolved alerts"},
+		{key: ":alerts [clear]", desc: "Open the alerts view / clear resolved alerts"},
+	}))
+	s.WriteString("\n")
+
+	s.WriteString(renderHelpSection("Events View", []helpEntry{
+		{key: keyLabel(kb.Events.Jump), desc: "Open the container, image, volume or network"},
+		{key: keyLabel(kb.Events.CycleType), desc: "Cycle the type filter"},
+		{key: ":events type/action <a,b>", desc: "Filter by event types or actions"},
+		{key: ":events container/project <name>", desc: "Filter by container glob or compose project"},
+		{key: ":events since <time>", desc: "Load history, e.g. 1h or an RFC3339 time"},
+		{key: ":events clear", desc: "Reset the filters"},
+	}))
+	s.WriteString("\n")
+
+	actions := []helpEntry{
+		{key: ":s / :S", desc: "Start/stop container, marked ones or project", mutating: true},
+		{key: keyLabel(c.Restart), desc: "Restart container, marked ones or project", mutating: true},
+		{key: keyLabel(c.Delete), desc: "Delete container/volume/image/network (asks first)", mutating: true},
+		{key: keyLabel(c.Logs), desc: "View logs"},
+		{key: keyLabel(c.Exec), desc: "Execute shell (docker exec)", mutating: true},
+		{key: keyLabel(c.Ports), desc: "View port mappings"},
+		{key: keyLabel(c.Env), desc: "View environment variables"},
+		{key: keyLabel(c.Stats), desc: "View live stats"},
+		{key: keyLabel(c.Inspect), desc: "View inspect (JSON)"},
+	}
+	if m.ReadOnly {
+		actions = slices.DeleteFunc(actions, func(e helpEntry) bool { return e.mutating })
+	}
+	s.WriteString(renderHelpSection("Container Actions", actions))
+	s.WriteString("\n")
+
 	s.WriteString(renderHelpSection("Logs View", []helpEntry{
-		{key: "j/k", desc: "Scroll logs up/down"},
-		{key: "g/G", desc: "Jump to top/bottom"},
-		{key: "?", desc: "Search in logs"},
-		{key: "n/N", desc: "Next/previous search result"},
-		{key: "f", desc: "Toggle live log follow"},
+		{key: keyPair(nav.Down, nav.Up), desc: "Scroll logs up/down"},
+		{key: keyPair(nav.Top, nav.Bottom), desc: "Jump to top/bottom"},
+		{key: keyLabel(logs.Search), desc: "Search in logs (/regex/ for a regex)"},
+		{key: keyPair(logs.NextResult, logs.PrevResult), desc: "Next/previous search result"},
+		{key: keyLabel(logs.Follow), desc: "Toggle live log follow"},
+		{key: keyLabel(logs.StreamFilter), desc: "Cycle streams: all/stdout/stderr"},
+		{key: keyLabel(logs.CycleSource), desc: "Project logs: show one service at a time"},
+		{key: keyLabel(logs.LoadOlder), desc: "Load older lines (also " + keyLabel(nav.Up) + " at the top)"},
+		{key: ":logs since", desc: "Reload logs since a time, e.g. :logs since 10m"},
+		{key: ":logs until", desc: "Reload logs up to an RFC3339 time"},
+		{key: ":logs all", desc: "Clear the time range"},
+		{key: keyLabel(logs.LevelFilter), desc: "Cycle minimum level: all/info/warn/error"},
+		{key: keyLabel(logs.JSONView), desc: "Toggle JSON column view"},
+		{key: keyLabel(logs.Expand), desc: "Expand selected line as a JSON tree"},
+		{key: ":filter k=v", desc: "Show JSON lines matching all terms (k!=v negates)"},
+		{key: ":svc <name>", desc: "Project logs: hide/show a service"},
+		{key: keyLabel(logs.SmartCase), desc: "Toggle smart-case search"},
+		{key: keyLabel(logs.FilterMode), desc: "Filter mode: only matches with context"},
+		{key: ":ctx <n>", desc: "Context lines around matches in filter mode"},
+		{key: ":w <path>", desc: "Save shown lines (-T: no timestamps)"},
+		{key: ":w! <path>", desc: "Save full history in background (.gz ok)"},
 		{key: ":noh", desc: "Clear search highlighting"},
 	}))
 	s.WriteString("\n")
 
 	s.WriteString(renderHelpSection("Ports View", []helpEntry{
-		{key: "j/k", desc: "Select port"},
-		{key: "o/enter", desc: "Open selected port in browser"},
+		{key: keyPair(nav.Down, nav.Up), desc: "Select port"},
+		{key: strings.Join(c.OpenPort, "/"), desc: "Open selected port in browser"},
 	}))
 	s.WriteString("\n")
 
 	s.WriteString(renderHelpSection("General", []helpEntry{
 		{key: ":q", desc: "Quit application"},
 		{key: ":help", desc: "Show this help"},
-		{key: "esc", desc: "Go back/close view"},
-		{key: "ctrl+c", desc: "Force quit"},
-		{key: ":", desc: "Enter command mode"},
+		{key: keyLabel(kb.Views.Back), desc: "Cancel running operations, else go back/close view"},
+		{key: ":cancel", desc: "Cancel running operations and log exports"},
+		{key: ":context [name]", desc: "List Docker contexts / switch to one"},
+		{key: keyLabel(kb.General.ForceQuit), desc: "Force quit"},
+		{key: keyLabel(kb.Commands.Enter), desc: "Enter command mode"},
 	}))
-	s.WriteString("\n")
-	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted)).Italic(true).Render("Press esc to close this help"))
 
-	helpContent := s.String()
+	return strings.TrimSuffix(s.String(), "\n")
+}
 
-	// Create a centered box for the help content
-	helpBox := lipgloss.NewStyle().
-		Width(width-4).
-		Height(height-2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(ColorInfo)).
-		Padding(1, 2).
-		Render(helpContent)
+// fit wraps s to width cells, as the panel borders used to, and cuts it
+// down to height lines so a view can never push the frame off screen.
+func fit(s string, width, height int) string {
+	if width > 0 {
+		s = ansi.Wrap(s, width, "")
+	}
+	return clip(s, width, height)
+}
 
-	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, helpBox)
+// maxLoadErrorLines caps how much of the list a load error may take.
+const maxLoadErrorLines = 3
+
+// loadErrorLines wraps a load error to width and cuts it to
+// maxLoadErrorLines, marking the cut with an ellipsis.
+func loadErrorLines(text string, width int) []string {
+	lines := strings.Split(ansi.Wrap(text, max(width, 1), " "), "\n")
+	if len(lines) > maxLoadErrorLines {
+		lines = lines[:maxLoadErrorLines]
+		last := strings.TrimRight(lines[maxLoadErrorLines-1], " ")
+		lines[maxLoadErrorLines-1] = ansi.Truncate(last+"…", max(width, 1), "…")
+	}
+	return lines
+}
+
+// clip cuts s down to height lines of at most width cells.
+func clip(s string, width, height int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > height {
+		lines = lines[:max(height, 0)]
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, max(width, 0), "")
+	}
+	return strings.Join(lines, "\n")
 }
 
 func renderPaneHeader(title, subtitle string) string {
@@ -1069,10 +1677,3 @@ func renderHelpSection(title string, entries []helpEntry) string {
 
 	return s.String()
 }
-
-func uiConfig(m *models.Model) config.UIConfig {
-	if m != nil && m.UIConfig != nil {
-		return *m.UIConfig
-	}
-	return *config.DefaultUI()
-}